@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/save"
+)
+
+// runExport implements `punkdoku export`: it writes the saved game in
+// progress so other Sudoku tools can pick it up.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "sdx", "format when writing to stdout: line|sdk|sdx|ss|opensudoku")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: punkdoku export [-format F] [file]")
+		fmt.Fprintln(fs.Output(), "Writes the saved game; with a file the format follows its extension.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	sg, err := save.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export: no saved game:", err)
+		return 1
+	}
+	if len(sg.Cages) > 0 {
+		fmt.Fprintln(os.Stderr, "export: none of the formats can store Killer cages; share the game's code instead")
+		return 1
+	}
+	if v, _ := game.ParseVariant(sg.Variant); v != game.Classic {
+		fmt.Fprintf(os.Stderr, "export: none of the formats can store the %s regions; share the game's code instead\n", v)
+		return 1
+	}
+	p := puzzleio.FromBoard(sg.Board())
+	p.Name = fmt.Sprintf("punkdoku %s %s", sg.Difficulty, sg.Seed)
+	if fs.NArg() > 0 {
+		if err := puzzleio.WriteFile(fs.Arg(0), []puzzleio.Puzzle{p}); err != nil {
+			fmt.Fprintln(os.Stderr, "export:", err)
+			return 1
+		}
+		return 0
+	}
+	f, err := puzzleio.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export:", err)
+		return 2
+	}
+	if err := puzzleio.Write(os.Stdout, f, []puzzleio.Puzzle{p}); err != nil {
+		fmt.Fprintln(os.Stderr, "export:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/save"
+)
+
+func TestExport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	g, err := game.ParseGrid("..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := game.NewBoardFromPuzzle(g)
+	sg := save.Game{Difficulty: "Hard", Seed: "s", Puzzle: g, Given: b.Given, Values: b.Values}
+
+	tests := []struct {
+		name    string
+		variant string
+		cages   []game.Cage
+		code    int
+		out     string
+		err     string
+	}{
+		{"classic", "", nil, 0, g.String() + "\n", ""},
+		{"x-sudoku", "X-Sudoku", nil, 1, "", "X-Sudoku regions"},
+		{"hyper", "Hyper", nil, 1, "", "Hyper regions"},
+		{"killer", "", []game.Cage{{Sum: 3, Cells: [][2]int{{0, 0}, {0, 1}}}}, 1, "", "Killer cages"},
+	}
+	for _, tt := range tests {
+		sg.Variant, sg.Cages = tt.variant, tt.cages
+		if err := save.Write(sg); err != nil {
+			t.Fatal(err)
+		}
+		var code int
+		out, errOut := capture(t, func() { code = runExport([]string{"-format", "line"}) })
+		if code != tt.code || out != tt.out || !strings.Contains(errOut, tt.err) {
+			t.Errorf("%s: exit %d, stdout %q, stderr %q", tt.name, code, out, errOut)
+		}
+	}
+}
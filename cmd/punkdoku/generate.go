@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/solver"
+)
+
+// generatedPuzzle is one entry of `punkdoku generate -format json`.
+type generatedPuzzle struct {
+	Difficulty string `json:"difficulty"`
+	Seed       string `json:"seed"`
+	Puzzle     string `json:"puzzle"`
+	Solution   string `json:"solution"`
+
+	grid generator.Grid
+}
+
+// runGenerate implements `punkdoku generate`: it prints puzzles to stdout
+// without starting the TUI.
+func runGenerate(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	count := fs.Int("n", 1, "number of puzzles")
+	diffName := fs.String("difficulty", "normal", "Difficulty: easy|normal|hard|lunatic|minimal")
+	seed := fs.String("seed", "", "seed word; puzzle i>1 uses <seed>/<i> (default: random)")
+	daily := fs.Bool("daily", false, "print today's daily puzzle (ignores -difficulty, -seed, -variant and -n)")
+	clues := fs.Int("clues", 0, "accept only puzzles with at most this many givens (best with -difficulty minimal)")
+	attempts := fs.Int("attempts", 0, "attempts per puzzle before giving up (default: per difficulty)")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (default: no limit)")
+	symName := fs.String("symmetry", "none", "clue layout: none|rotational|diagonal|mirror|four-fold")
+	varName := fs.String("variant", "classic", "extra regions: classic|x-sudoku|hyper")
+	format := fs.String("format", "line", "output format: line|grid|json|sdk|sdx|ss|opensudoku")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: punkdoku generate [-n N] [-difficulty D] [-seed S] [-symmetry Y] [-variant V] [-clues N] [-timeout T] [-daily] [-format F]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	d, err := generator.ParseDifficulty(*diffName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "generate:", err)
+		return 2
+	}
+	params := generator.ParamsFor(d)
+	if params.Symmetry, err = generator.ParseSymmetry(*symName); err != nil {
+		fmt.Fprintln(os.Stderr, "generate:", err)
+		return 2
+	}
+	if params.Variant, err = game.ParseVariant(*varName); err != nil {
+		fmt.Fprintln(os.Stderr, "generate:", err)
+		return 2
+	}
+	params.MaxClues = *clues
+	if *attempts > 0 {
+		params.MaxAttempts = *attempts
+	}
+	var fileFormat puzzleio.Format
+	if *format != "grid" && *format != "json" {
+		f, err := puzzleio.ParseFormat(*format)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "generate:", err)
+			return 2
+		}
+		fileFormat = f
+	}
+
+	var out []generatedPuzzle
+	if *daily {
+		now := time.Now()
+		g, err := generator.GenerateDaily(now)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "generate:", err)
+			return 1
+		}
+		diff := "daily-" + strings.ToLower(generator.DailyDifficulty(now).String())
+		out = append(out, newGeneratedPuzzle(diff, generator.DailySeed(now), g, game.Classic))
+	} else {
+		base := *seed
+		if base == "" {
+			base = strconv.FormatInt(time.Now().UnixNano(), 36)
+		}
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		for i := 1; i <= *count; i++ {
+			s := packSeed(base, i)
+			// attempts run on every CPU; the seed still fixes the result
+			g, err := generator.GenerateContext(ctx, params, s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "generate: puzzle %d (seed %q): %v\n", i, s, err)
+				return 1
+			}
+			out = append(out, newGeneratedPuzzle(strings.ToLower(d.String()), s, g, params.Variant))
+		}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintln(os.Stderr, "generate:", err)
+			return 1
+		}
+	case "grid":
+		for i, p := range out {
+			if i > 0 { fmt.Println() }
+			fmt.Printf("# %s seed=%s\n", p.Difficulty, p.Seed)
+			writePrettyGrid(os.Stdout, p.Puzzle)
+		}
+	default:
+		ps := make([]puzzleio.Puzzle, len(out))
+		for i, p := range out {
+			ps[i] = puzzleio.FromGrid(p.grid)
+			ps[i].Name = fmt.Sprintf("punkdoku %s %s", p.Difficulty, p.Seed)
+		}
+		if err := puzzleio.Write(os.Stdout, fileFormat, ps); err != nil {
+			fmt.Fprintln(os.Stderr, "generate:", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// packSeed derives the seed of the i-th puzzle (1-based) of a pack; the first
+// one uses the seed as typed so it matches the same seed in the TUI.
+func packSeed(seed string, i int) string {
+	if i == 1 { return seed }
+	return fmt.Sprintf("%s/%d", seed, i)
+}
+
+func newGeneratedPuzzle(diff, seed string, g generator.Grid, v game.Variant) generatedPuzzle {
+	sol := solver.Grid(g)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	solution := ""
+	if solver.SolveWith(ctx, &sol, generator.SolverRegions(v)) {
+		solution = game.Grid(sol).String()
+	}
+	if v != game.Classic {
+		diff += "-" + strings.ToLower(v.String())
+	}
+	return generatedPuzzle{Difficulty: diff, Seed: seed, Puzzle: game.Grid(g).String(), Solution: solution, grid: g}
+}
+
+// writePrettyGrid prints an 81-char line as a boxed 9x9 grid.
+func writePrettyGrid(w io.Writer, line string) {
+	sep := "+-------+-------+-------+"
+	fmt.Fprintln(w, sep)
+	for r := 0; r < 9; r++ {
+		var b strings.Builder
+		for c := 0; c < 9; c++ {
+			if c%3 == 0 { b.WriteString("| ") }
+			b.WriteByte(line[r*9+c])
+			b.WriteByte(' ')
+		}
+		b.WriteString("|")
+		fmt.Fprintln(w, b.String())
+		if r%3 == 2 { fmt.Fprintln(w, sep) }
+	}
+}
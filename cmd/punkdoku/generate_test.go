@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/solver"
+)
+
+func TestRunGenerate(t *testing.T) {
+	var code int
+	out, errOut := capture(t, func() { code = run([]string{"generate", "-n", "2", "-difficulty", "hard", "-seed", "abc"}) })
+	if code != 0 || errOut != "" {
+		t.Fatalf("exit %d, stderr %q", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	seeds := []string{"abc", "abc/2"}
+	if len(lines) != len(seeds) {
+		t.Fatalf("%d lines, want %d:\n%s", len(lines), len(seeds), out)
+	}
+	// the CLI defaults to no symmetry, unlike the menu
+	p := generator.ParamsFor(generator.Hard)
+	for i, s := range seeds {
+		g, err := generator.GenerateWithParams(p, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := game.Grid(g).String(); lines[i] != want {
+			t.Errorf("seed %q:\n got %s\nwant %s", s, lines[i], want)
+		}
+	}
+}
+
+func TestRunGenerateJSON(t *testing.T) {
+	var code int
+	out, errOut := capture(t, func() {
+		code = run([]string{"generate", "-format", "json", "-difficulty", "easy", "-variant", "x-sudoku", "-seed", "j"})
+	})
+	if code != 0 || errOut != "" {
+		t.Fatalf("exit %d, stderr %q", code, errOut)
+	}
+	var ps []generatedPuzzle
+	if err := json.Unmarshal([]byte(out), &ps); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if len(ps) != 1 || ps[0].Difficulty != "easy-x-sudoku" || ps[0].Seed != "j" {
+		t.Fatalf("got %+v", ps)
+	}
+	puzzle, err := game.ParseGrid(ps[0].Puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol := solver.Grid(puzzle)
+	if !solver.SolveWith(context.Background(), &sol, generator.SolverRegions(game.XSudoku)) || game.Grid(sol).String() != ps[0].Solution {
+		t.Errorf("solution %s does not solve %s", ps[0].Solution, ps[0].Puzzle)
+	}
+}
@@ -1,25 +1,132 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"punkdoku/internal/config"
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/sharecode"
 	"punkdoku/internal/ui"
 )
 
 func main() {
-	// Legacy flags kept but ignored when menu is used
-	_ = flag.Bool("daily", false, "Generate daily puzzle")
-	_ = flag.String("difficulty", "normal", "Difficulty: easy|normal|hard|lunatic")
-	flag.Parse()
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches the command line and returns the exit status.
+func run(args []string) int {
+	// Headless subcommands run without Bubble Tea
+	if len(args) > 0 {
+		switch args[0] {
+		case "generate":
+			return runGenerate(args[1:])
+		case "solve":
+			return runSolve(args[1:])
+		case "export":
+			return runExport(args[1:])
+		case "play":
+			if len(args) != 2 {
+				fmt.Fprintln(os.Stderr, "usage: punkdoku play <file>")
+				return 2
+			}
+			return runTUI(args[1])
+		}
+	}
+
+	fs := flag.NewFlagSet("punkdoku", flag.ContinueOnError)
+	// -daily and -difficulty predate the menu; they now open that game directly
+	daily := fs.Bool("daily", false, "play today's daily puzzle")
+	diffName := fs.String("difficulty", "", "play a new game: easy|normal|hard|lunatic|minimal")
+	puzzle := fs.String("puzzle", "", "play a custom puzzle: 81 chars ('.' or '0' = blank) or a file path")
+	code := fs.String("code", "", "play the puzzle of a share code")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: punkdoku [-daily | -difficulty D | -puzzle P | -code C]")
+		fmt.Fprintln(fs.Output(), "       punkdoku generate|solve|export|play ...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "punkdoku: unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+	c, err := startCode(*code, *daily, *diffName, *puzzle != "")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "punkdoku:", err)
+		return 2
+	}
+	if c != "" {
+		return runCode(c)
+	}
+	return runTUI(*puzzle)
+}
+
+// startCode folds -code, -daily and -difficulty into the share code of the
+// game to open, empty for the menu or a -puzzle. A -difficulty game is carved
+// with the configured symmetry and variant, like the menu's.
+func startCode(code string, daily bool, diffName string, puzzle bool) (string, error) {
+	n := 0
+	for _, set := range []bool{code != "", daily, diffName != "", puzzle} {
+		if set { n++ }
+	}
+	if n > 1 {
+		return "", errors.New("-daily, -difficulty, -puzzle and -code each start a game; pick one")
+	}
+	switch {
+	case daily:
+		return sharecode.ForDaily(time.Now()), nil
+	case diffName != "":
+		d, err := generator.ParseDifficulty(diffName)
+		if err != nil { return "", err }
+		cfg, _ := config.Load()
+		sym, _ := generator.ParseSymmetry(cfg.Symmetry)
+		v, _ := game.ParseVariant(cfg.Variant)
+		return sharecode.ForSeed(d, sym, v, strconv.FormatInt(time.Now().UnixNano(), 36)), nil
+	}
+	return code, nil
+}
 
+// runTUI starts Bubble Tea on the menu, or directly on a custom puzzle when
+// one is given; invalid puzzles are reported before the screen switches.
+func runTUI(puzzle string) int {
 	cfg, _ := config.Load()
 	app := ui.NewApp(cfg)
+	if puzzle != "" {
+		p, err := ui.ImportPuzzle(puzzle)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "puzzle:", err)
+			return 1
+		}
+		app = ui.NewAppWithPuzzle(cfg, p)
+	}
+	return runApp(app)
+}
+
+// runCode starts Bubble Tea on the game of a share code.
+func runCode(code string) int {
+	cfg, _ := config.Load()
+	app, err := ui.NewAppWithCode(cfg, code)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "code:", err)
+		return 1
+	}
+	return runApp(app)
+}
+
+func runApp(app ui.App) int {
+	defer app.Close()
 	if _, err := tea.NewProgram(app, tea.WithAltScreen()).Run(); err != nil {
 		fmt.Fprintln(os.Stderr, "ui error:", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/sharecode"
+)
+
+// capture runs fn with stdout and stderr redirected, returning both.
+func capture(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	read := func(f *os.File) string {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	out, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errf, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldOut, oldErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = out, errf
+	defer func() { os.Stdout, os.Stderr = oldOut, oldErr }()
+	fn()
+	return read(out), read(errf)
+}
+
+// Bad command lines stop with status 2 before any screen opens.
+func TestRunRejects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-daily", "-difficulty", "hard"}, "pick one"},
+		{[]string{"-difficulty", "hard", "-code", "H1-abc"}, "pick one"},
+		{[]string{"-difficulty", "brutal"}, `unknown difficulty "brutal"`},
+		{[]string{"-nope"}, "flag provided but not defined"},
+		{[]string{"bogus"}, `unknown command "bogus"`},
+		{[]string{"play"}, "usage: punkdoku play"},
+		{[]string{"play", "a.txt", "b.txt"}, "usage: punkdoku play"},
+		{[]string{"generate", "-difficulty", "brutal"}, `unknown difficulty "brutal"`},
+		{[]string{"solve", "-nope"}, "usage: punkdoku solve"},
+	}
+	for _, tt := range tests {
+		var code int
+		out, errOut := capture(t, func() { code = run(tt.args) })
+		if code != 2 || out != "" || !strings.Contains(errOut, tt.err) {
+			t.Errorf("run(%q) = %d, stdout %q, stderr %q; want 2 and %q", tt.args, code, out, errOut, tt.err)
+		}
+	}
+}
+
+// The legacy flags open the same games as their share codes.
+func TestStartCode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s, err := startCode("", true, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, err := sharecode.Parse(s); err != nil || c.Kind != sharecode.Daily || c.Seed != generator.DailySeed(time.Now()) {
+		t.Errorf("-daily: %q = %+v, %v", s, c, err)
+	}
+
+	s, err = startCode("", false, "hard", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a fresh HOME has the default config: rotational classic
+	c, err := sharecode.Parse(s)
+	if err != nil || c.Kind != sharecode.Seeded || c.Difficulty != generator.Hard || c.Symmetry != generator.Rotational || c.Variant != game.Classic || c.Seed == "" {
+		t.Errorf("-difficulty hard: %q = %+v, %v", s, c, err)
+	}
+
+	if s, err := startCode("H1-abc", false, "", false); s != "H1-abc" || err != nil {
+		t.Errorf("-code: %q, %v", s, err)
+	}
+	if s, err := startCode("", false, "", true); s != "" || err != nil {
+		t.Errorf("-puzzle: %q, %v", s, err)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/solver"
+)
+
+// solveResult is one entry of `punkdoku solve -json`.
+type solveResult struct {
+	Source    string   `json:"source"`
+	Puzzle    string   `json:"puzzle"`
+	Status    string   `json:"status"` // unique|multiple|none|unknown|invalid
+	Solutions []string `json:"solutions,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	ElapsedMs float64  `json:"elapsedMs"`
+}
+
+// puzzleInput is one puzzle line together with where it came from.
+type puzzleInput struct {
+	source string
+	text   string
+}
+
+// runSolve implements `punkdoku solve [-json] [file...]`, reading stdin when
+// no files are given.
+func runSolve(args []string) int {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	timeout := fs.Duration("timeout", 10*time.Second, "time limit per puzzle")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: punkdoku solve [-json] [-timeout 10s] [file...]")
+		fmt.Fprintln(fs.Output(), "Reads one 81-char puzzle per line ('.' or '0' = blank); '#' starts a comment.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var inputs []puzzleInput
+	if fs.NArg() == 0 {
+		in, err := readPuzzleLines("stdin", os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "solve:", err)
+			return 1
+		}
+		inputs = in
+	}
+	for _, name := range fs.Args() {
+		in, err := readPuzzleFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "solve:", err)
+			return 1
+		}
+		inputs = append(inputs, in...)
+	}
+
+	results := make([]solveResult, 0, len(inputs))
+	exit := 0
+	for _, in := range inputs {
+		r := solveOne(in, *timeout)
+		if r.Status == "invalid" { exit = 1 }
+		results = append(results, r)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintln(os.Stderr, "solve:", err)
+			return 1
+		}
+		return exit
+	}
+	for i, r := range results {
+		if i > 0 { fmt.Println() }
+		fmt.Printf("%s: %s (%.2fms)\n", r.Source, r.Status, r.ElapsedMs)
+		if r.Error != "" {
+			fmt.Println("  " + r.Error)
+		}
+		for _, s := range r.Solutions {
+			fmt.Println(s)
+		}
+	}
+	return exit
+}
+
+// readPuzzleFile reads 81-char lines, or any other puzzleio format by extension.
+func readPuzzleFile(name string) ([]puzzleInput, error) {
+	if puzzleio.FormatForPath(name) != puzzleio.Line {
+		ps, err := puzzleio.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]puzzleInput, len(ps))
+		for i, p := range ps {
+			out[i] = puzzleInput{source: fmt.Sprintf("%s#%d", name, i+1), text: game.Grid(p.Grid()).String()}
+		}
+		return out, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readPuzzleLines(name, f)
+}
+
+// readPuzzleLines returns the non-empty, non-comment lines of r.
+func readPuzzleLines(name string, r io.Reader) ([]puzzleInput, error) {
+	var out []puzzleInput
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" { continue }
+		out = append(out, puzzleInput{source: fmt.Sprintf("%s:%d", name, line), text: text})
+	}
+	return out, sc.Err()
+}
+
+// solveOne parses and solves a single puzzle, looking for at most two solutions.
+func solveOne(in puzzleInput, timeout time.Duration) solveResult {
+	res := solveResult{Source: in.source, Puzzle: in.text}
+	g, err := game.ParseGrid(in.text)
+	if err != nil {
+		res.Status = "invalid"
+		res.Error = err.Error()
+		return res
+	}
+	res.Puzzle = g.String()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	sols, err := solver.Solutions(ctx, solver.Grid(g), 2)
+	res.ElapsedMs = float64(time.Since(start).Microseconds()) / 1000
+	for _, s := range sols {
+		res.Solutions = append(res.Solutions, game.Grid(s).String())
+	}
+	switch {
+	case err != nil && len(sols) < 2:
+		res.Status = "unknown"
+		res.Error = "search timed out"
+	case len(sols) == 0:
+		res.Status = "none"
+		if hasDuplicates(g) {
+			res.Error = "givens repeat a digit in a row, column or box"
+		}
+	case len(sols) == 1:
+		res.Status = "unique"
+	default:
+		res.Status = "multiple"
+	}
+	return res
+}
+
+func hasDuplicates(g game.Grid) bool {
+	for _, row := range game.DuplicateMapAll(g) {
+		for _, d := range row {
+			if d { return true }
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	uniquePuzzle   = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+	uniqueSolution = "483921657967345821251876493548132976729564138136798245372689514814253769695417382"
+)
+
+func TestRunSolve(t *testing.T) {
+	empty := strings.Repeat(".", 81)
+	clash := "55" + strings.Repeat(".", 79)
+	tests := []struct {
+		name   string
+		lines  []string
+		code   int
+		status []string
+	}{
+		{"unique", []string{uniquePuzzle}, 0, []string{"unique"}},
+		{"mixed", []string{"# a pack", uniquePuzzle, "", empty + " # blank", clash}, 0, []string{"unique", "multiple", "none"}},
+		{"invalid line", []string{uniquePuzzle, "12345"}, 1, []string{"unique", "invalid"}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "pack.txt")
+		if err := os.WriteFile(path, []byte(strings.Join(tt.lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var code int
+		out, errOut := capture(t, func() { code = run([]string{"solve", "-json", path}) })
+		if code != tt.code || errOut != "" {
+			t.Errorf("%s: exit %d, stderr %q; want %d", tt.name, code, errOut, tt.code)
+		}
+		var res []solveResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("%s: %v\n%s", tt.name, err, out)
+		}
+		if len(res) != len(tt.status) {
+			t.Fatalf("%s: %d results, want %d", tt.name, len(res), len(tt.status))
+		}
+		for i, r := range res {
+			if r.Status != tt.status[i] {
+				t.Errorf("%s: %s is %s, want %s", tt.name, r.Source, r.Status, tt.status[i])
+			}
+			if r.Status == "unique" && (len(r.Solutions) != 1 || r.Solutions[0] != uniqueSolution) {
+				t.Errorf("%s: %s solutions %q", tt.name, r.Source, r.Solutions)
+			}
+		}
+	}
+}
+
+func TestRunSolveStdin(t *testing.T) {
+	in, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.WriteString(uniquePuzzle + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdin
+	os.Stdin = in
+	defer func() { os.Stdin = old }()
+
+	var code int
+	out, _ := capture(t, func() { code = run([]string{"solve"}) })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if code != 0 || len(lines) != 2 || !strings.HasPrefix(lines[0], "stdin:1: unique (") || lines[1] != uniqueSolution {
+		t.Errorf("exit %d, stdout:\n%s", code, out)
+	}
+}
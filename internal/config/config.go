@@ -13,6 +13,10 @@ type Config struct {
 	Theme        string              `yaml:"theme"`
 	AutoCheck    bool                `yaml:"autoCheck"`
 	TimerEnabled bool                `yaml:"timerEnabled"`
+	// Symmetry of generated clue layouts: none|rotational|diagonal|mirror|four-fold
+	Symmetry     string              `yaml:"symmetry"`
+	// Variant of new difficulty games: classic|x-sudoku|hyper
+	Variant      string              `yaml:"variant"`
 	Bindings     map[string][]string `yaml:"bindings"`
 }
 
@@ -21,6 +25,8 @@ func Default() Config {
 		Theme:        "dark",
 		AutoCheck:    true,
 		TimerEnabled: true,
+		Symmetry:     "rotational",
+		Variant:      "classic",
 		Bindings:     map[string][]string{},
 	}
 }
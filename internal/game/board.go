@@ -6,17 +6,50 @@ import (
 
 type Grid [9][9]uint8
 
+// Notes holds pencil-mark candidates per cell; bit v-1 set means digit v is noted.
+type Notes [9][9]uint16
+
+// NoteBit returns the candidate mask bit for digit v (1-9).
+func NoteBit(v uint8) uint16 { return 1 << (v - 1) }
+
+// NoteEdit records a pencil-mark change on a peer cell caused by a move.
+type NoteEdit struct {
+	Row  int
+	Col  int
+	Prev uint16
+	Next uint16
+}
+
+// Move records one edit of a cell. Value and note edits share the same
+// record so undo/redo can restore both from Prev*/Next*. Peers holds the
+// notes stripped from other cells when a value was placed.
 type Move struct {
-	Row int
-	Col int
-	Prev uint8
-	Next uint8
-	At   time.Time
+	Row       int
+	Col       int
+	Prev      uint8
+	Next      uint8
+	PrevNotes uint16
+	NextNotes uint16
+	Peers     []NoteEdit
+	At        time.Time
 }
 
 type Board struct {
 	Given [9][9]bool
 	Values Grid
+	Notes  Notes
+	// Regions are the extra houses of a variant (X-Sudoku diagonals, Hyper
+	// windows) and Cages those of Killer Sudoku; classic boards leave both nil.
+	Regions []Region
+	Cages   []Cage
+}
+
+// Constraints lists the board's rules beyond rows, columns and blocks.
+func (b *Board) Constraints() []Constraint {
+	var out []Constraint
+	for _, r := range b.Regions { out = append(out, r) }
+	for _, k := range b.Cages { out = append(out, k) }
+	return out
 }
 
 func NewBoardFromPuzzle(p Grid) Board {
@@ -44,10 +77,89 @@ func (b *Board) SetValue(row, col int, v uint8) (prev uint8, ok bool) {
 	return prev, true
 }
 
+// ToggleNote flips pencil mark v on an empty, non-given cell.
+func (b *Board) ToggleNote(row, col int, v uint8) (prev, next uint16, ok bool) {
+	prev = b.Notes[row][col]
+	if b.Given[row][col] || b.Values[row][col] != 0 || v < 1 || v > 9 {
+		return prev, prev, false
+	}
+	next = prev ^ NoteBit(v)
+	b.Notes[row][col] = next
+	return prev, next, true
+}
+
+// ClearNotes removes every pencil mark from a non-given cell.
+func (b *Board) ClearNotes(row, col int) (prev uint16, ok bool) {
+	prev = b.Notes[row][col]
+	if b.Given[row][col] || prev == 0 {
+		return prev, false
+	}
+	b.Notes[row][col] = 0
+	return prev, true
+}
+
+// EliminateNote strips digit v from the notes of every peer of (row, col),
+// region and cage mates included, and returns the cells that actually changed.
+func (b *Board) EliminateNote(row, col int, v uint8) []NoteEdit {
+	if v < 1 || v > 9 { return nil }
+	var edits []NoteEdit
+	bit := NoteBit(v)
+	peers := Peers(row, col)
+	for _, k := range b.Constraints() {
+		if !covers(k, row, col) { continue }
+		for _, p := range k.Members() {
+			if p != [2]int{row, col} { peers = append(peers, p) }
+		}
+	}
+	for _, p := range peers {
+		prev := b.Notes[p[0]][p[1]]
+		if prev&bit == 0 { continue }
+		b.Notes[p[0]][p[1]] = prev &^ bit
+		edits = append(edits, NoteEdit{Row: p[0], Col: p[1], Prev: prev, Next: prev &^ bit})
+	}
+	return edits
+}
+
+// Undo restores the cells touched by mv to their state before the move.
+func (b *Board) Undo(mv Move) {
+	b.Values[mv.Row][mv.Col] = mv.Prev
+	b.Notes[mv.Row][mv.Col] = mv.PrevNotes
+	for _, e := range mv.Peers {
+		b.Notes[e.Row][e.Col] = e.Prev
+	}
+}
+
+// Redo re-applies mv to its cell and peers.
+func (b *Board) Redo(mv Move) {
+	b.Values[mv.Row][mv.Col] = mv.Next
+	b.Notes[mv.Row][mv.Col] = mv.NextNotes
+	for _, e := range mv.Peers {
+		b.Notes[e.Row][e.Col] = e.Next
+	}
+}
+
+// Peers returns the 20 cells sharing a row, column or 3x3 block with (row, col).
+func Peers(row, col int) [][2]int {
+	out := make([][2]int, 0, 20)
+	for i := 0; i < 9; i++ {
+		if i != col { out = append(out, [2]int{row, i}) }
+		if i != row { out = append(out, [2]int{i, col}) }
+	}
+	r0 := (row/3)*3
+	c0 := (col/3)*3
+	for r := r0; r < r0+3; r++ {
+		for c := c0; c < c0+3; c++ {
+			if r != row && c != col { out = append(out, [2]int{r, c}) }
+		}
+	}
+	return out
+}
+
 func InBounds(row, col int) bool { return row >= 0 && row < 9 && col >= 0 && col < 9 }
 
-// DuplicateMap marks cells that duplicate the selected cell's value across row/col/box.
-func DuplicateMap(g Grid, selRow, selCol int) [9][9]bool {
+// DuplicateMap marks cells that duplicate the selected cell's value across
+// row/col/box, and across the extra regions or cages (see Constraint) holding it.
+func DuplicateMap(g Grid, selRow, selCol int, rules ...Constraint) [9][9]bool {
 	var dup [9][9]bool
 	v := g[selRow][selCol]
 	if v == 0 { return dup }
@@ -64,11 +176,19 @@ func DuplicateMap(g Grid, selRow, selCol int) [9][9]bool {
 			}
 		}
 	}
+	for _, k := range rules {
+		if !covers(k, selRow, selCol) { continue }
+		for _, p := range k.Members() {
+			if p != [2]int{selRow, selCol} && g[p[0]][p[1]] == v { dup[p[0]][p[1]] = true }
+		}
+	}
 	return dup
 }
 
 // DuplicateMapAll marks any duplicates in rows, columns, or 3x3 blocks across the entire grid.
-func DuplicateMapAll(g Grid) [9][9]bool {
+// Extra rules add their own marks: repeats in a region, and for cages also
+// digits that don't fit the sum (over it, or off it once full).
+func DuplicateMapAll(g Grid, rules ...Constraint) [9][9]bool {
 	var dup [9][9]bool
 	// rows
 	for r := 0; r < 9; r++ {
@@ -112,12 +232,17 @@ func DuplicateMapAll(g Grid) [9][9]bool {
 			}
 		}
 	}
+	// regions and cages
+	for _, k := range rules {
+		k.Mark(g, &dup)
+	}
 	return dup
 }
 
-// ConflictMap marks cells that violate Sudoku constraints (duplicates), excluding givens.
-func ConflictMap(values Grid, given [9][9]bool) [9][9]bool {
-	all := DuplicateMapAll(values)
+// ConflictMap marks cells that violate Sudoku constraints (duplicates, plus
+// whatever the extra rules flag), excluding givens.
+func ConflictMap(values Grid, given [9][9]bool, rules ...Constraint) [9][9]bool {
+	all := DuplicateMapAll(values, rules...)
 	var bad [9][9]bool
 	for r := 0; r < 9; r++ {
 		for c := 0; c < 9; c++ {
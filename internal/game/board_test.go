@@ -0,0 +1,109 @@
+package game
+
+import "testing"
+
+func TestToggleNote(t *testing.T) {
+	var p Grid
+	p[0][0] = 5
+	b := NewBoardFromPuzzle(p)
+	b.Values[0][1] = 3
+	tests := []struct {
+		name     string
+		row, col int
+		v        uint8
+		want     uint16
+		ok       bool
+	}{
+		{"set 4", 1, 1, 4, NoteBit(4), true},
+		{"set 9", 1, 1, 9, NoteBit(4) | NoteBit(9), true},
+		{"clear 4", 1, 1, 4, NoteBit(9), true},
+		{"given cell", 0, 0, 4, 0, false},
+		{"filled cell", 0, 1, 4, 0, false},
+		{"digit 0", 1, 1, 0, NoteBit(9), false},
+		{"digit 10", 1, 1, 10, NoteBit(9), false},
+	}
+	for _, tt := range tests {
+		before := b.Notes[tt.row][tt.col]
+		prev, next, ok := b.ToggleNote(tt.row, tt.col, tt.v)
+		if ok != tt.ok || prev != before || next != tt.want || b.Notes[tt.row][tt.col] != tt.want {
+			t.Errorf("%s: prev %09b next %09b ok %v, want prev %09b next %09b ok %v",
+				tt.name, prev, next, ok, before, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNoteUndoRedo(t *testing.T) {
+	var b Board
+	var undo []Move
+	for _, v := range []uint8{2, 7, 2} {
+		prev, next, _ := b.ToggleNote(4, 4, v)
+		undo = append(undo, Move{Row: 4, Col: 4, PrevNotes: prev, NextNotes: next})
+	}
+	if b.Notes[4][4] != NoteBit(7) {
+		t.Fatalf("notes = %09b, want only 7", b.Notes[4][4])
+	}
+	want := []uint16{NoteBit(2) | NoteBit(7), NoteBit(2), 0}
+	for i := len(undo) - 1; i >= 0; i-- {
+		b.Undo(undo[i])
+		if got := b.Notes[4][4]; got != want[len(undo)-1-i] {
+			t.Errorf("after undo %d: %09b, want %09b", len(undo)-i, got, want[len(undo)-1-i])
+		}
+	}
+	for i, mv := range undo {
+		b.Redo(mv)
+		if b.Notes[4][4] != mv.NextNotes {
+			t.Errorf("after redo %d: %09b, want %09b", i+1, b.Notes[4][4], mv.NextNotes)
+		}
+	}
+}
+
+// Placing a digit strips it from every peer's notes, region and cage mates
+// included, and undo puts every stripped mark back.
+func TestEliminateNoteUndo(t *testing.T) {
+	b := Board{Regions: XSudoku.Regions(), Cages: []Cage{{Sum: 10, Cells: [][2]int{{4, 4}, {4, 5}, {8, 0}}}}}
+	mark := [][2]int{
+		{4, 0},         // row
+		{0, 4},         // column
+		{3, 3},         // block
+		{0, 0}, {8, 8}, // main diagonal
+		{0, 8}, {8, 0}, // anti-diagonal; r9c1 is a cage mate too
+		{1, 2},         // no house in common: kept
+	}
+	for _, p := range mark {
+		b.Notes[p[0]][p[1]] = NoteBit(6) | NoteBit(1)
+	}
+	before := b.Notes
+	prev, _ := b.SetValue(4, 4, 6)
+	mv := Move{Row: 4, Col: 4, Prev: prev, Next: 6, Peers: b.EliminateNote(4, 4, 6)}
+	if len(mv.Peers) != len(mark)-1 {
+		t.Errorf("%d peers edited, want %d", len(mv.Peers), len(mark)-1)
+	}
+	for _, p := range mark {
+		want := NoteBit(1)
+		if p == [2]int{1, 2} {
+			want |= NoteBit(6)
+		}
+		if got := b.Notes[p[0]][p[1]]; got != want {
+			t.Errorf("r%dc%d notes = %09b, want %09b", p[0]+1, p[1]+1, got, want)
+		}
+	}
+	after := b.Notes
+	b.Undo(mv)
+	if b.Notes != before || b.Values[4][4] != 0 {
+		t.Error("undo did not restore the peers' notes and the cell")
+	}
+	b.Redo(mv)
+	if b.Notes != after || b.Values[4][4] != 6 {
+		t.Error("redo did not strip the peers' notes again")
+	}
+}
+
+// A cage mate outside every row, column, block and region still loses the mark.
+func TestEliminateNoteCage(t *testing.T) {
+	b := Board{Cages: []Cage{{Sum: 4, Cells: [][2]int{{0, 0}, {8, 8}}}}}
+	b.Notes[8][8] = NoteBit(1) | NoteBit(3)
+	edits := b.EliminateNote(0, 0, 3)
+	if len(edits) != 1 || edits[0] != (NoteEdit{Row: 8, Col: 8, Prev: NoteBit(1) | NoteBit(3), Next: NoteBit(1)}) {
+		t.Errorf("edits = %+v", edits)
+	}
+}
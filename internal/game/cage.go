@@ -0,0 +1,53 @@
+package game
+
+// Cage is a Killer Sudoku cage: its cells must hold distinct digits that add
+// up to Sum.
+type Cage struct {
+	Sum   int      `json:"sum"`
+	Cells [][2]int `json:"cells"`
+}
+
+func (k Cage) Members() [][2]int { return k.Cells }
+
+// Mark flags digits repeated in the cage, and every filled cell when its
+// digits overshoot the sum, or miss it once the cage is full.
+func (k Cage) Mark(g Grid, bad *[9][9]bool) {
+	markRepeats(g, k.Cells, bad)
+	sum, filled := 0, 0
+	for _, p := range k.Cells {
+		if v := g[p[0]][p[1]]; v != 0 {
+			sum += int(v)
+			filled++
+		}
+	}
+	if sum <= k.Sum && (filled < len(k.Cells) || sum == k.Sum) { return }
+	for _, p := range k.Cells {
+		if g[p[0]][p[1]] != 0 { bad[p[0]][p[1]] = true }
+	}
+}
+
+// Anchor returns the cell that carries the sum label: the top-most cell,
+// left-most among ties.
+func (k Cage) Anchor() [2]int {
+	a := k.Cells[0]
+	for _, p := range k.Cells[1:] {
+		if p[0] < a[0] || (p[0] == a[0] && p[1] < a[1]) { a = p }
+	}
+	return a
+}
+
+// CageIndex maps every cell to the index of its cage in cages, -1 for none.
+func CageIndex(cages []Cage) [9][9]int {
+	var idx [9][9]int
+	for r := range idx {
+		for c := range idx[r] {
+			idx[r][c] = -1
+		}
+	}
+	for i, k := range cages {
+		for _, p := range k.Cells {
+			idx[p[0]][p[1]] = i
+		}
+	}
+	return idx
+}
@@ -0,0 +1,49 @@
+package game
+
+import "testing"
+
+func TestCageMark(t *testing.T) {
+	k := Cage{Sum: 10, Cells: [][2]int{{0, 0}, {0, 1}, {1, 0}}}
+	tests := []struct {
+		name   string
+		digits [3]uint8
+		want   [3]bool
+	}{
+		{"partial and below the sum", [3]uint8{2, 5, 0}, [3]bool{}},
+		{"full with the right sum", [3]uint8{2, 5, 3}, [3]bool{}},
+		{"repeated digit", [3]uint8{4, 4, 0}, [3]bool{true, true, false}},
+		{"partial over the sum", [3]uint8{9, 3, 0}, [3]bool{true, true, false}},
+		{"full with the wrong sum", [3]uint8{1, 2, 3}, [3]bool{true, true, true}},
+	}
+	for _, tt := range tests {
+		var g Grid
+		for i, p := range k.Cells {
+			g[p[0]][p[1]] = tt.digits[i]
+		}
+		var bad [9][9]bool
+		k.Mark(g, &bad)
+		for i, p := range k.Cells {
+			if bad[p[0]][p[1]] != tt.want[i] {
+				t.Errorf("%s: r%dc%d marked %v, want %v", tt.name, p[0]+1, p[1]+1, bad[p[0]][p[1]], tt.want[i])
+			}
+		}
+		// the cage never marks cells outside of it
+		bad[0][0], bad[0][1], bad[1][0] = false, false, false
+		if bad != ([9][9]bool{}) {
+			t.Errorf("%s: marked cells outside the cage", tt.name)
+		}
+	}
+}
+
+func TestDuplicateMapAllCages(t *testing.T) {
+	var g Grid
+	g[0][0], g[4][4] = 7, 7 // not in a shared house, but in one cage
+	k := Cage{Sum: 15, Cells: [][2]int{{0, 0}, {4, 4}}}
+	bad := DuplicateMapAll(g, k)
+	if !bad[0][0] || !bad[4][4] {
+		t.Errorf("repeat inside a cage not flagged")
+	}
+	if bad = DuplicateMapAll(g); bad[0][0] || bad[4][4] {
+		t.Errorf("flagged without the cage")
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns the grid as an 81-character line, row by row, with '.' for blanks.
+func (g Grid) String() string {
+	var b strings.Builder
+	b.Grow(81)
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if v := g[r][c]; v == 0 {
+				b.WriteByte('.')
+			} else {
+				b.WriteByte('0' + v)
+			}
+		}
+	}
+	return b.String()
+}
+
+// ParseGrid reads an 81-cell puzzle row by row. Digits 1-9 are clues, '0' and
+// '.' are blanks; whitespace is ignored so pasted 9-line grids also work.
+func ParseGrid(s string) (Grid, error) {
+	var g Grid
+	n := 0
+	for _, ch := range s {
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			continue
+		case ch == '.' || ch == '0' || (ch >= '1' && ch <= '9'):
+			if n < 81 && ch >= '1' && ch <= '9' {
+				g[n/9][n%9] = uint8(ch - '0')
+			}
+			n++
+		default:
+			return Grid{}, fmt.Errorf("invalid character %q at cell %d", ch, n+1)
+		}
+	}
+	if n != 81 {
+		return Grid{}, fmt.Errorf("expected 81 cells, got %d", n)
+	}
+	return g, nil
+}
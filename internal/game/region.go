@@ -0,0 +1,121 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a rule a variant adds on top of rows, columns and blocks:
+// an extra Region or a Killer Cage. No digit repeats among its Members.
+type Constraint interface {
+	Members() [][2]int
+	// Mark flags the cells of g that break the rule.
+	Mark(g Grid, bad *[9][9]bool)
+}
+
+// Region is an extra house: like a row, column or block, its nine cells hold
+// every digit once.
+type Region struct {
+	Name  string
+	Cells [][2]int
+}
+
+func (r Region) Members() [][2]int { return r.Cells }
+
+func (r Region) Mark(g Grid, bad *[9][9]bool) { markRepeats(g, r.Cells, bad) }
+
+// covers reports whether (row, col) is one of the members of k.
+func covers(k Constraint, row, col int) bool {
+	for _, p := range k.Members() {
+		if p[0] == row && p[1] == col { return true }
+	}
+	return false
+}
+
+// markRepeats flags the filled cells of cells whose digit appears twice.
+func markRepeats(g Grid, cells [][2]int, bad *[9][9]bool) {
+	count := map[uint8]int{}
+	for _, p := range cells {
+		if v := g[p[0]][p[1]]; v != 0 { count[v]++ }
+	}
+	for _, p := range cells {
+		if v := g[p[0]][p[1]]; v != 0 && count[v] > 1 { bad[p[0]][p[1]] = true }
+	}
+}
+
+// Variant picks the extra regions a puzzle is played with.
+type Variant int
+
+const (
+	Classic Variant = iota
+	// XSudoku adds both main diagonals.
+	XSudoku
+	// Hyper (Windoku) adds four 3x3 windows, one row and column in from each corner.
+	Hyper
+)
+
+// String returns the name used in the menu and config.
+func (v Variant) String() string {
+	switch v {
+	case XSudoku:
+		return "X-Sudoku"
+	case Hyper:
+		return "Hyper"
+	}
+	return "Classic"
+}
+
+// ParseVariant maps a case-insensitive name (classic|x-sudoku|hyper) to a
+// Variant; "x" and "windoku" are accepted too.
+func ParseVariant(s string) (Variant, error) {
+	for v := Classic; v <= Hyper; v++ {
+		if strings.EqualFold(s, v.String()) {
+			return v, nil
+		}
+	}
+	switch strings.ToLower(s) {
+	case "x", "xsudoku":
+		return XSudoku, nil
+	case "windoku":
+		return Hyper, nil
+	}
+	return Classic, fmt.Errorf("unknown variant %q (want classic|x-sudoku|hyper)", s)
+}
+
+// Regions returns the extra regions of v, none for Classic.
+func (v Variant) Regions() []Region {
+	switch v {
+	case XSudoku:
+		main := Region{Name: "main diagonal"}
+		anti := Region{Name: "anti-diagonal"}
+		for i := 0; i < 9; i++ {
+			main.Cells = append(main.Cells, [2]int{i, i})
+			anti.Cells = append(anti.Cells, [2]int{i, 8 - i})
+		}
+		return []Region{main, anti}
+	case Hyper:
+		var out []Region
+		for w, at := range [4][2]int{{1, 1}, {1, 5}, {5, 1}, {5, 5}} {
+			win := Region{Name: fmt.Sprintf("window %d", w+1)}
+			for r := at[0]; r < at[0]+3; r++ {
+				for c := at[1]; c < at[1]+3; c++ {
+					win.Cells = append(win.Cells, [2]int{r, c})
+				}
+			}
+			out = append(out, win)
+		}
+		return out
+	}
+	return nil
+}
+
+// RegionMap marks the cells that belong to any of regions.
+func RegionMap(regions []Region) [9][9]bool {
+	var in [9][9]bool
+	for _, r := range regions {
+		for _, p := range r.Cells {
+			in[p[0]][p[1]] = true
+		}
+	}
+	return in
+}
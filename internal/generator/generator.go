@@ -1,8 +1,14 @@
 package generator
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/solver"
 )
 
 // Difficulty represents puzzle difficulty tiers.
@@ -13,8 +19,16 @@ const (
 	Normal
 	Hard
 	Lunatic
+	// Minimal carves until no single clue can go without losing uniqueness,
+	// whatever techniques that ends up needing.
+	Minimal
 )
 
+// Version identifies the seeded generation algorithm. The same seed, difficulty
+// and Version always give the same puzzle; bump it whenever a change (PRNG,
+// carving order, node budgets, grading bands) alters seeded output.
+const Version = 1
+
 // DailySeed returns a stable seed based on UTC date (YYYY-MM-DD).
 func DailySeed(t time.Time) string {
 	utc := t.UTC()
@@ -23,59 +37,223 @@ func DailySeed(t time.Time) string {
 
 // Params controls generation knobs derived from difficulty.
 type Params struct {
-	// number of blanks/removed cells; higher -> harder
+	// number of blanks/removed cells; higher -> harder. 81 or more carves to
+	// a minimal puzzle: every remaining clue (orbit, with Symmetry) is needed.
 	RemovedCells int
-	// backtracking timeout to avoid worst-cases
-	Timeout time.Duration
+	// MaxClues, when > 0, rejects attempts that end with more givens, e.g. 22
+	// together with a minimal carve for the sparsest grids.
+	MaxClues int
+	// MinTechnique..MaxTechnique is the band the hardest technique needed by
+	// the logical solver must fall in (solver.Backtracking = beyond the solver).
+	MinTechnique solver.Technique
+	MaxTechnique solver.Technique
+	// ExtraCells lets a too-easy puzzle keep carving past RemovedCells.
+	ExtraCells int
+	// MaxAttempts bounds how many full solutions are tried to hit the band.
+	MaxAttempts int
+	// Symmetry is the clue layout; NoSymmetry carves cells independently.
+	Symmetry Symmetry
+	// Variant adds regions (X-Sudoku diagonals, Hyper windows) that the
+	// solution, uniqueness checks and grading all honor.
+	Variant game.Variant
 }
 
-// paramsFor maps Difficulty to generation parameters.
-func paramsFor(d Difficulty) Params {
+// ParamsFor maps Difficulty to generation parameters.
+//   - Easy: naked singles only
+//   - Normal: needs hidden singles
+//   - Hard: locked candidates, subsets or X-Wing
+//   - Lunatic: Swordfish, XY-Wing, coloring or beyond
+//   - Minimal: minimal puzzle, any technique
+func ParamsFor(d Difficulty) Params {
 	switch d {
 	case Easy:
-		return Params{RemovedCells: 38, Timeout: 150 * time.Millisecond}
+		return Params{RemovedCells: 38, MinTechnique: solver.NakedSingle, MaxTechnique: solver.NakedSingle, MaxAttempts: 50}
 	case Normal:
-		return Params{RemovedCells: 46, Timeout: 150 * time.Millisecond}
+		return Params{RemovedCells: 46, MinTechnique: solver.HiddenSingle, MaxTechnique: solver.HiddenSingle, ExtraCells: 4, MaxAttempts: 100}
 	case Hard:
-		return Params{RemovedCells: 52, Timeout: 200 * time.Millisecond}
+		return Params{RemovedCells: 52, MinTechnique: solver.PointingPair, MaxTechnique: solver.XWing, ExtraCells: 4, MaxAttempts: 200}
 	case Lunatic:
-		return Params{RemovedCells: 58, Timeout: 250 * time.Millisecond}
+		return Params{RemovedCells: 58, MinTechnique: solver.Swordfish, MaxTechnique: solver.Backtracking, ExtraCells: 2, MaxAttempts: 200}
+	case Minimal:
+		return Params{RemovedCells: 81, MinTechnique: solver.NakedSingle, MaxTechnique: solver.Backtracking, MaxAttempts: 500}
 	default:
-		return Params{RemovedCells: 46, Timeout: 150 * time.Millisecond}
+		return ParamsFor(Normal)
+	}
+}
+
+// String returns the menu label of the difficulty.
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Normal:
+		return "Normal"
+	case Hard:
+		return "Hard"
+	case Lunatic:
+		return "Lunatic"
+	case Minimal:
+		return "Minimal"
 	}
+	return "Unknown"
+}
+
+// ParseDifficulty maps a case-insensitive name (easy|normal|hard|lunatic|minimal) to a Difficulty.
+func ParseDifficulty(s string) (Difficulty, error) {
+	for d := Easy; d <= Minimal; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return Normal, fmt.Errorf("unknown difficulty %q (want easy|normal|hard|lunatic|minimal)", s)
+}
+
+// Grade rates g by the hardest technique the logical solver needs.
+func Grade(g Grid) solver.Grade {
+	return solver.GradePuzzle(convertToSolverGrid(g))
+}
+
+// GradeVariant is Grade for a puzzle played with the extra regions of v.
+func GradeVariant(g Grid, v game.Variant) solver.Grade {
+	return solver.GradePuzzleWith(convertToSolverGrid(g), SolverRegions(v))
 }
 
 // Grid is a 9x9 Sudoku grid. 0 represents empty.
 type Grid [9][9]uint8
 
-// ErrTimeout is returned when generation exceeds the configured timeout.
+// ErrTimeout is returned when generation exceeds its search-node budget.
 var ErrTimeout = errors.New("generation timed out")
 
+// ErrNotUnique is returned when a carved puzzle could not be proven to have exactly one solution.
+var ErrNotUnique = errors.New("puzzle uniqueness could not be verified")
+
+// ErrNotMinimal is returned when a minimal carve could not be proven to need every clue.
+var ErrNotMinimal = errors.New("puzzle minimality could not be verified")
+
+// ErrNoBandMatch is returned when no attempt produced a puzzle in the requested difficulty band.
+var ErrNoBandMatch = errors.New("no puzzle matched the difficulty band")
+
 // Generate creates a Sudoku puzzle with the given difficulty and seed.
 // - If seed is empty, uses current time for randomness.
 // - For Daily mode, pass seed from DailySeed(date).
 // Returns a puzzle grid with 0 as blanks, aimed at single-solution.
 func Generate(d Difficulty, seed string) (Grid, error) {
-	p := paramsFor(d)
-	return generateWithParams(p, seed)
+	return GenerateWithParams(ParamsFor(d), seed)
 }
 
-// GenerateDaily creates a daily puzzle based on UTC date.
+// dailyRotation is the daily tier per weekday: it ramps up from an easy start
+// of the week to Sunday's lunatic puzzle.
+var dailyRotation = [7]Difficulty{
+	time.Sunday:    Lunatic,
+	time.Monday:    Easy,
+	time.Tuesday:   Easy,
+	time.Wednesday: Normal,
+	time.Thursday:  Normal,
+	time.Friday:    Hard,
+	time.Saturday:  Hard,
+}
+
+// DailyDifficulty returns the tier of the daily puzzle for the UTC date of t.
+func DailyDifficulty(t time.Time) Difficulty {
+	return dailyRotation[t.UTC().Weekday()]
+}
+
+// GenerateDaily creates a daily puzzle based on UTC date, at that weekday's tier.
 func GenerateDaily(date time.Time) (Grid, error) {
-	return Generate(Normal, DailySeed(date))
+	return Generate(DailyDifficulty(date), DailySeed(date))
+}
+
+// GenerateWithParams regenerates until a puzzle lands in the band of p. Start
+// from ParamsFor and adjust, e.g. the Symmetry. Attempt seeds are derived from
+// seed, so seeded output stays reproducible.
+func GenerateWithParams(p Params, seed string) (Grid, error) {
+	attempts := p.MaxAttempts
+	if attempts < 1 { attempts = 1 }
+	lastErr := ErrNoBandMatch
+	for i := 0; i < attempts; i++ {
+		puzzle, err := generateAttempt(context.Background(), p, attemptSeed(seed, i))
+		if err == nil {
+			return puzzle, nil
+		}
+		lastErr = err
+	}
+	return Grid{}, lastErr
+}
+
+// verifyUnique re-checks the finished puzzle; an over-budget (Unknown) check
+// counts as failure so an unproven puzzle is never returned.
+func verifyUnique(g Grid, regions []solver.Region) bool {
+	return solver.CheckUniquenessNodesWith(convertToSolverGrid(g), regions, verifyNodes) == solver.Unique
+}
+
+// provenMinimal reports whether every remaining orbit of clues is needed:
+// without it the puzzle must provably have a second solution.
+func provenMinimal(ctx context.Context, puzzle Grid, sym Symmetry, regions []solver.Region) bool {
+	for _, orbit := range orbits(sym) {
+		if ctx.Err() != nil { return false }
+		if puzzle[orbit[0]/9][orbit[0]%9] == 0 { continue }
+		g := puzzle
+		for _, idx := range orbit {
+			g[idx/9][idx%9] = 0
+		}
+		if solver.CheckUniquenessNodesWith(convertToSolverGrid(g), regions, verifyNodes) != solver.Multiple {
+			return false
+		}
+	}
+	return true
+}
+
+func attemptSeed(seed string, i int) string {
+	if seed == "" || i == 0 {
+		return seed
+	}
+	return fmt.Sprintf("%s#%d", seed, i)
 }
 
-// generateWithParams contains the core generation pipeline.
-func generateWithParams(p Params, seed string) (Grid, error) {
+// generateAttempt builds one full solution and carves it, carving further
+// while the puzzle is still easier than the band. ctx only aborts an attempt,
+// it never changes what a finished attempt returns.
+func generateAttempt(ctx context.Context, p Params, seed string) (Grid, error) {
 	// 1) Create a full valid solution via randomized backtracking
-	full, err := randomizedFullSolution(seed, p.Timeout)
+	full, err := randomizedFullSolution(seed, p.Variant.Regions())
 	if err != nil {
 		return Grid{}, err
 	}
+	regions := SolverRegions(p.Variant)
 	// 2) Remove cells according to difficulty while keeping uniqueness if possible
-	puzzle, err := carveCellsUnique(full, p.RemovedCells, seed, p.Timeout)
-	if err != nil {
-		return Grid{}, err
+	for extra := 0; extra <= p.ExtraCells; extra += 2 {
+		puzzle, err := carveCellsUnique(ctx, full, p.RemovedCells+extra, seed, p.Symmetry, regions)
+		if err != nil {
+			return Grid{}, err
+		}
+		if !verifyUnique(puzzle, regions) {
+			return Grid{}, ErrNotUnique
+		}
+		// a carve check over budget keeps its orbit, so prove every clue is needed
+		if p.RemovedCells >= 81 && !provenMinimal(ctx, puzzle, p.Symmetry, regions) {
+			return Grid{}, ErrNotMinimal
+		}
+		if p.MaxClues > 0 && clueCount(puzzle) > p.MaxClues {
+			continue
+		}
+		// 3) Grade with the logical solver and check the band
+		gr := solver.GradePuzzleWith(convertToSolverGrid(puzzle), regions)
+		if gr.Hardest > p.MaxTechnique {
+			break // carving more only makes it harder
+		}
+		if gr.Hardest >= p.MinTechnique {
+			return puzzle, nil
+		}
+	}
+	return Grid{}, ErrNoBandMatch
+}
+
+func clueCount(g Grid) int {
+	n := 0
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if g[r][c] != 0 { n++ }
+		}
 	}
-	return puzzle, nil
+	return n
 }
@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/solver"
+)
+
+// Seeded puzzles are handed out as share codes and dailies, so the same seed
+// must keep giving the same puzzle. If one of these changes, bump Version.
+func TestVersion(t *testing.T) {
+	if Version != 1 {
+		t.Fatalf("Version = %d; update the golden puzzles below along with it", Version)
+	}
+}
+
+func TestSeededGolden(t *testing.T) {
+	tests := []struct {
+		d    Difficulty
+		sym  Symmetry
+		want string
+	}{
+		{Easy, NoSymmetry, "7..4.12.........49241.6.537.52193476..65.8...9..7.....5..21..833276.4...168.357.4"},
+		{Normal, NoSymmetry, "7....12.........49.41...537.5.193.76...5.8...9..7.....5...1..83.276.....168.357.."},
+		{Hard, NoSymmetry, "9.....1..6.....24....54186959.37..2.37169...4....5...3.....5.9...2.......6.7....."},
+		{Lunatic, NoSymmetry, "2.4.17.5.85........1.45.3.........85.79........86.27..3.2...4......2467.........."},
+		{Minimal, NoSymmetry, "7....12.........49.41...5......93..6...5.8...9..7.....5...1..83..76.....1.8..57.."},
+		{Hard, Rotational, "....1..598.32...4....4....2.217....5....8....5....271.3....6....9...46.374..3...."},
+		{Hard, Diagonal, "94..2..376....7.45..7.....9.....86..3...9...4.2.1..9.....2.5...75.......169.8...."},
+		{Hard, Mirror, "..1.9.2.....256.....67.13....4.2.8..9..4.7..2.23...46.6..9.8..4..26.59....9...6.."},
+		{Hard, FourFold, ".6.....8...2.6.5..7..1.8..69...7...22..4.3..75...1...46..2.9..3..1.4.6...2.....7."},
+	}
+	for _, tt := range tests {
+		p := ParamsFor(tt.d)
+		p.Symmetry = tt.sym
+		g, err := GenerateWithParams(p, "punk")
+		if err != nil {
+			t.Errorf("%v/%v: %v", tt.d, tt.sym, err)
+			continue
+		}
+		if got := game.Grid(g).String(); got != tt.want {
+			t.Errorf("%v/%v:\n got %s\nwant %s", tt.d, tt.sym, got, tt.want)
+		}
+	}
+}
+
+func TestDailyGolden(t *testing.T) {
+	date := time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC)
+	if d := DailyDifficulty(date); d != Hard {
+		t.Fatalf("DailyDifficulty(Saturday) = %v, want Hard", d)
+	}
+	g, err := GenerateDaily(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ".3.....864.....9.......43....92.71.3...95.7....4...6...581.....9.2......3..52...."
+	if got := game.Grid(g).String(); got != want {
+		t.Errorf("daily %s:\n got %s\nwant %s", DailySeed(date), got, want)
+	}
+}
+
+func mustParse(t *testing.T, s string) Grid {
+	t.Helper()
+	g, err := game.ParseGrid(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Grid(g)
+}
+
+func inBand(p Params, tech solver.Technique) bool {
+	return tech >= p.MinTechnique && tech <= p.MaxTechnique
+}
+
+// Well-known puzzles land in the band of the tier they are sold as.
+func TestGradeBands(t *testing.T) {
+	tests := []struct {
+		d      Difficulty
+		puzzle string
+	}{
+		{Easy, "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."},
+		{Normal, "2...8.3...6..7..84.3.5..2.9...1.54.8.........4.27.6...3.1..7.4.72..4..6...4.1...3"},
+		{Hard, "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"},
+		{Lunatic, "2..........7.5.34.......8.5..5.716..3.98...1....5..78...3....6.....43.....1.6...9"},
+		{Lunatic, "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."},
+	}
+	for _, tt := range tests {
+		gr := Grade(mustParse(t, tt.puzzle))
+		if !inBand(ParamsFor(tt.d), gr.Hardest) {
+			t.Errorf("%s graded %v, outside the %v band", tt.puzzle, gr.Hardest, tt.d)
+		}
+		for other := Easy; other <= Lunatic; other++ {
+			if other != tt.d && inBand(ParamsFor(other), gr.Hardest) {
+				t.Errorf("%s graded %v, also inside the %v band", tt.puzzle, gr.Hardest, other)
+			}
+		}
+	}
+}
+
+func TestGenerateHitsBand(t *testing.T) {
+	for d := Easy; d <= Minimal; d++ {
+		p := ParamsFor(d)
+		for _, seed := range []string{"band", "band/2", "band/3"} {
+			g, err := GenerateWithParams(p, seed)
+			if err != nil {
+				t.Errorf("%v %s: %v", d, seed, err)
+				continue
+			}
+			if gr := Grade(g); !inBand(p, gr.Hardest) {
+				t.Errorf("%v %s: hardest %v outside [%v, %v]", d, seed, gr.Hardest, p.MinTechnique, p.MaxTechnique)
+			}
+		}
+	}
+}
+
+// Every clue of a Minimal puzzle is needed: without any one of them the
+// puzzle has a second solution.
+func TestMinimalNeedsEveryClue(t *testing.T) {
+	for _, seed := range []string{"min", "min/2", "min/3", "min/4"} {
+		g, err := Generate(Minimal, seed)
+		if err != nil {
+			t.Fatalf("%s: %v", seed, err)
+		}
+		for i := 0; i < 81; i++ {
+			if g[i/9][i%9] == 0 {
+				continue
+			}
+			less := g
+			less[i/9][i%9] = 0
+			if u := solver.CheckUniquenessNodes(convertToSolverGrid(less), verifyNodes); u != solver.Multiple {
+				t.Errorf("%s: without r%dc%d the puzzle is %v", seed, i/9+1, i%9+1, u)
+			}
+		}
+	}
+}
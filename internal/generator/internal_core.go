@@ -1,30 +1,37 @@
 package generator
 
 import (
-	"math/rand"
-	"time"
+	"context"
 
+	"punkdoku/internal/game"
 	"punkdoku/internal/solver"
 )
 
+// Search-node budgets of the seeded path. They replace wall-clock timeouts so
+// a slow machine can't cut generation short and produce a different puzzle;
+// changing them changes seeded output and needs a Version bump.
+const (
+	fillNodes   = 1 << 20
+	carveNodes  = 1 << 16
+	verifyNodes = 1 << 20
+)
+
 // randomizedFullSolution builds a complete valid Sudoku solution using randomized DFS.
-func randomizedFullSolution(seed string, timeout time.Duration) (Grid, error) {
-	var rng *rand.Rand
-	if seed == "" {
-		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
-	} else {
-		rng = rand.New(rand.NewSource(int64(hashStringToUint64(seed))))
-	}
-	deadline := time.Now().Add(timeout)
+// The search is bounded by fillNodes rather than a clock so a seed always
+// yields the same grid (or the same ErrTimeout). Extra regions (see
+// game.Variant) must hold every digit once too.
+func randomizedFullSolution(seed string, regions []game.Region) (Grid, error) {
+	rng := newRNG(seed, 0)
 	var g Grid
-	if fillCellRandom(&g, 0, 0, rng, deadline) {
+	nodes := 0
+	if fillCellRandom(&g, 0, 0, rng, &nodes, regions) {
 		return g, nil
 	}
 	return Grid{}, ErrTimeout
 }
 
-func fillCellRandom(g *Grid, row, col int, rng *rand.Rand, deadline time.Time) bool {
-	if time.Now().After(deadline) {
+func fillCellRandom(g *Grid, row, col int, rng *rng, nodes *int, regions []game.Region) bool {
+	if *nodes++; *nodes > fillNodes {
 		return false
 	}
 	nextRow, nextCol := row, col+1
@@ -36,11 +43,11 @@ func fillCellRandom(g *Grid, row, col int, rng *rand.Rand, deadline time.Time) b
 		return true
 	}
 	vals := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	rng.Shuffle(len(vals), func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	rng.shuffle(len(vals), func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
 	for _, v := range vals {
-		if isSafe(*g, row, col, v) {
+		if isSafe(*g, row, col, v, regions) {
 			g[row][col] = v
-			if fillCellRandom(g, nextRow, nextCol, rng, deadline) {
+			if fillCellRandom(g, nextRow, nextCol, rng, nodes, regions) {
 				return true
 			}
 			g[row][col] = 0
@@ -49,7 +56,7 @@ func fillCellRandom(g *Grid, row, col int, rng *rand.Rand, deadline time.Time) b
 	return false
 }
 
-func isSafe(g Grid, row, col int, v uint8) bool {
+func isSafe(g Grid, row, col int, v uint8, regions []game.Region) bool {
 	for i := 0; i < 9; i++ {
 		if g[row][i] == v || g[i][col] == v {
 			return false
@@ -64,37 +71,58 @@ func isSafe(g Grid, row, col int, v uint8) bool {
 			}
 		}
 	}
+	for _, reg := range regions {
+		if !inRegion(reg, row, col) { continue }
+		for _, p := range reg.Cells {
+			if g[p[0]][p[1]] == v { return false }
+		}
+	}
 	return true
 }
 
+func inRegion(reg game.Region, row, col int) bool {
+	for _, p := range reg.Cells {
+		if p[0] == row && p[1] == col { return true }
+	}
+	return false
+}
+
+// SolverRegions converts the extra regions of v for the solver.
+func SolverRegions(v game.Variant) []solver.Region {
+	var out []solver.Region
+	for _, reg := range v.Regions() {
+		sr := solver.Region{Name: reg.Name}
+		for i, p := range reg.Cells {
+			sr.Cells[i] = p[0]*9 + p[1]
+		}
+		out = append(out, sr)
+	}
+	return out
+}
+
 // carveCellsUnique removes cells while trying to keep a single solution.
-func carveCellsUnique(full Grid, targetRemoved int, seed string, timeout time.Duration) (Grid, error) {
+// Cells go in symmetric orbits, all or none, so the givens keep the pattern.
+func carveCellsUnique(ctx context.Context, full Grid, targetRemoved int, seed string, sym Symmetry, regions []solver.Region) (Grid, error) {
 	puzzle := full
-	var rng *rand.Rand
-	if seed == "" {
-		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
-	} else {
-		rng = rand.New(rand.NewSource(int64(hashStringToUint64(seed) + 0x9e3779b97f4a7c15)))
-	}
-	deadline := time.Now().Add(timeout)
-	cells := make([]int, 81)
-	for i := 0; i < 81; i++ { cells[i] = i }
-	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	rng := newRNG(seed, 1)
+	cells := orbits(sym)
+	rng.shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
 	removed := 0
-	for _, idx := range cells {
-		if time.Now().After(deadline) {
-			break
+	for _, orbit := range cells {
+		if err := ctx.Err(); err != nil {
+			return Grid{}, err
 		}
-		r := idx / 9
-		c := idx % 9
-		backup := puzzle[r][c]
-		puzzle[r][c] = 0
-		// Check uniqueness using solver.CountSolutions up to 2
-		if solver.CountSolutions(convertToSolverGrid(puzzle), 50*time.Millisecond, 2) != 1 {
-			puzzle[r][c] = backup
+		for _, idx := range orbit {
+			puzzle[idx/9][idx%9] = 0
+		}
+		// Only a definite Unique lets the orbit go; Unknown (over budget) keeps it
+		if solver.CheckUniquenessNodesWith(convertToSolverGrid(puzzle), regions, carveNodes) != solver.Unique {
+			for _, idx := range orbit {
+				puzzle[idx/9][idx%9] = full[idx/9][idx%9]
+			}
 			continue
 		}
-		removed++
+		removed += len(orbit)
 		if removed >= targetRemoved {
 			break
 		}
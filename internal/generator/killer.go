@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"context"
+	"sort"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/solver"
+)
+
+// Killer is a Killer Sudoku: cages with sums over Solution, plus the few givens
+// (usually none) the cages alone leave undecided.
+type Killer struct {
+	Givens   Grid
+	Solution Grid
+	Cages    []game.Cage
+}
+
+// Killer search budgets; like the classic ones they fix seeded output.
+const (
+	killerNodes     = 1 << 16
+	killerAttempts  = 20
+	maxKillerGivens = 12
+)
+
+// killerSizes weights cage sizes: mostly pairs and triples, some larger.
+var killerSizes = []int{2, 2, 2, 3, 3, 3, 4, 4, 5}
+
+// GenerateKiller creates a Killer Sudoku with a unique solution, proven by the
+// cage-aware solver. Attempt seeds follow the classic pattern, so a seed
+// always gives the same cages and givens; cancelling ctx returns ctx.Err().
+func GenerateKiller(ctx context.Context, seed string) (Killer, error) {
+	lastErr := ErrNotUnique
+	for i := 0; i < killerAttempts; i++ {
+		k, err := killerAttempt(ctx, attemptSeed(seed, i))
+		if err == nil {
+			return k, nil
+		}
+		if ctx.Err() != nil {
+			return Killer{}, ctx.Err()
+		}
+		lastErr = err
+	}
+	return Killer{}, lastErr
+}
+
+// killerAttempt cages one full solution, then reveals cells where two
+// solutions still disagree until the cages and givens pin a single one.
+func killerAttempt(ctx context.Context, seed string) (Killer, error) {
+	full, err := randomizedFullSolution(seed, nil)
+	if err != nil {
+		return Killer{}, err
+	}
+	rng := newRNG(seed, 2)
+	cages := buildCages(full, rng)
+	sc := make([]solver.Cage, len(cages))
+	for i, k := range cages {
+		sc[i].Sum = k.Sum
+		for _, p := range k.Cells {
+			sc[i].Cells = append(sc[i].Cells, p[0]*9+p[1])
+		}
+	}
+	order := make([]int, 81)
+	for i := range order { order[i] = i }
+	rng.shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	var givens Grid
+	for n := 0; n <= maxKillerGivens; n++ {
+		if err := ctx.Err(); err != nil {
+			return Killer{}, err
+		}
+		sols, expired := solver.KillerSolutions(convertToSolverGrid(givens), sc, 2, killerNodes)
+		if !expired && len(sols) == 1 {
+			return Killer{Givens: givens, Solution: full, Cages: cages}, nil
+		}
+		// reveal the first cell (in seeded order) the two solutions disagree
+		// on; over budget, simply the next empty one
+		for _, idx := range order {
+			r, c := idx/9, idx%9
+			if givens[r][c] != 0 { continue }
+			if len(sols) == 2 && sols[0][r][c] == sols[1][r][c] { continue }
+			givens[r][c] = full[r][c]
+			break
+		}
+	}
+	return Killer{}, ErrNotUnique
+}
+
+// buildCages partitions the grid into orthogonally connected cages without
+// repeated digits. A cell left alone merges into a neighbouring cage when that
+// keeps the digits distinct.
+func buildCages(full Grid, rng *rng) []game.Cage {
+	var owner [81]int
+	for i := range owner { owner[i] = -1 }
+	order := make([]int, 81)
+	for i := range order { order[i] = i }
+	rng.shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	var cages [][]int
+	for _, start := range order {
+		if owner[start] >= 0 { continue }
+		size := killerSizes[rng.intn(len(killerSizes))]
+		cells := []int{start}
+		used := uint16(1) << full[start/9][start%9]
+		owner[start] = len(cages)
+		for len(cells) < size {
+			var next []int
+			for _, idx := range cells {
+				for _, nb := range neighbours(idx) {
+					if owner[nb] >= 0 || used&(1<<full[nb/9][nb%9]) != 0 || containsInt(next, nb) { continue }
+					next = append(next, nb)
+				}
+			}
+			if len(next) == 0 { break }
+			nb := next[rng.intn(len(next))]
+			cells = append(cells, nb)
+			used |= 1 << full[nb/9][nb%9]
+			owner[nb] = len(cages)
+		}
+		if len(cells) == 1 {
+			if k := mergeTarget(full, owner, cages, start); k >= 0 {
+				cages[k] = append(cages[k], start)
+				owner[start] = k
+				continue
+			}
+		}
+		cages = append(cages, cells)
+	}
+
+	out := make([]game.Cage, len(cages))
+	for i, cells := range cages {
+		sort.Ints(cells)
+		for _, idx := range cells {
+			out[i].Cells = append(out[i].Cells, [2]int{idx / 9, idx % 9})
+			out[i].Sum += int(full[idx/9][idx%9])
+		}
+	}
+	return out
+}
+
+// mergeTarget returns a neighbouring cage that can take cell idx without a
+// repeated digit, -1 if there is none.
+func mergeTarget(full Grid, owner [81]int, cages [][]int, idx int) int {
+	v := full[idx/9][idx%9]
+	for _, nb := range neighbours(idx) {
+		k := owner[nb]
+		if k < 0 || k >= len(cages) || len(cages[k]) >= 5 { continue }
+		clash := false
+		for _, c := range cages[k] {
+			if full[c/9][c%9] == v { clash = true }
+		}
+		if !clash { return k }
+	}
+	return -1
+}
+
+// neighbours returns the orthogonally adjacent cells of idx.
+func neighbours(idx int) []int {
+	r, c := idx/9, idx%9
+	out := make([]int, 0, 4)
+	if r > 0 { out = append(out, idx-9) }
+	if r < 8 { out = append(out, idx+9) }
+	if c > 0 { out = append(out, idx-1) }
+	if c < 8 { out = append(out, idx+1) }
+	return out
+}
+
+func containsInt(xs []int, x int) bool {
+	for _, y := range xs {
+		if y == x { return true }
+	}
+	return false
+}
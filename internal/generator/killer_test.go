@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/solver"
+)
+
+func solverCages(cages []game.Cage) []solver.Cage {
+	out := make([]solver.Cage, len(cages))
+	for i, k := range cages {
+		out[i].Sum = k.Sum
+		for _, p := range k.Cells {
+			out[i].Cells = append(out[i].Cells, p[0]*9+p[1])
+		}
+	}
+	return out
+}
+
+func TestGenerateKillerUnique(t *testing.T) {
+	for _, seed := range []string{"cage", "cage/2"} {
+		k, err := GenerateKiller(context.Background(), seed)
+		if err != nil {
+			t.Fatalf("%s: %v", seed, err)
+		}
+		for r := 0; r < 9; r++ {
+			for c := 0; c < 9; c++ {
+				if v := k.Givens[r][c]; v != 0 && v != k.Solution[r][c] {
+					t.Errorf("%s: given %d at r%dc%d, solution has %d", seed, v, r+1, c+1, k.Solution[r][c])
+				}
+			}
+		}
+		g := convertToSolverGrid(k.Givens)
+		if u := solver.CheckKillerUniquenessNodes(g, solverCages(k.Cages), verifyNodes); u != solver.Unique {
+			t.Errorf("%s: with cages %v, want Unique", seed, u)
+		}
+		if u := solver.CheckUniquenessNodes(g, verifyNodes); u != solver.Multiple {
+			t.Errorf("%s: without cages %v, want Multiple", seed, u)
+		}
+		if k2, _ := GenerateKiller(context.Background(), seed); k2.Givens != k.Givens || len(k2.Cages) != len(k.Cages) {
+			t.Errorf("%s: seeded Killer changed between runs", seed)
+		}
+	}
+}
+
+// Cages cover every cell once, stay orthogonally connected, never repeat a
+// digit of the solution and sum to it.
+func TestBuildCages(t *testing.T) {
+	full, err := randomizedFullSolution("split", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cages := buildCages(full, newRNG("split", 2))
+	var owner [9][9]int
+	for i, k := range cages {
+		if len(k.Cells) == 0 || len(k.Cells) > 5 {
+			t.Errorf("cage %d has %d cells", i, len(k.Cells))
+		}
+		sum, used := 0, map[uint8]bool{}
+		for _, p := range k.Cells {
+			if owner[p[0]][p[1]] != 0 {
+				t.Errorf("r%dc%d is in cages %d and %d", p[0]+1, p[1]+1, owner[p[0]][p[1]]-1, i)
+			}
+			owner[p[0]][p[1]] = i + 1
+			v := full[p[0]][p[1]]
+			if used[v] {
+				t.Errorf("cage %d repeats %d", i, v)
+			}
+			used[v] = true
+			sum += int(v)
+		}
+		if sum != k.Sum {
+			t.Errorf("cage %d sums to %d, labelled %d", i, sum, k.Sum)
+		}
+		// flood from the first cell; every member must be reached
+		reached := map[[2]int]bool{k.Cells[0]: true}
+		queue := [][2]int{k.Cells[0]}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for _, nb := range neighbours(p[0]*9 + p[1]) {
+				q := [2]int{nb / 9, nb % 9}
+				if !reached[q] && containsCell(k.Cells, q) {
+					reached[q] = true
+					queue = append(queue, q)
+				}
+			}
+		}
+		if len(reached) != len(k.Cells) {
+			t.Errorf("cage %d is not connected: %v", i, k.Cells)
+		}
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if owner[r][c] == 0 {
+				t.Errorf("r%dc%d is in no cage", r+1, c+1)
+			}
+		}
+	}
+}
+
+func containsCell(cells [][2]int, p [2]int) bool {
+	for _, q := range cells {
+		if q == p { return true }
+	}
+	return false
+}
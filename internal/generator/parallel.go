@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// GenerateContext is GenerateWithParams with its attempts spread over one
+// worker per CPU. Attempts keep the numbering of the sequential loop and the
+// lowest-numbered success wins, so a seed yields the same puzzle as
+// GenerateWithParams however the workers are scheduled. Without a seed the
+// first puzzle to finish is returned. Cancelling ctx stops every worker and
+// returns ctx.Err().
+func GenerateContext(ctx context.Context, p Params, seed string) (Grid, error) {
+	attempts := p.MaxAttempts
+	if attempts < 1 { attempts = 1 }
+	workers := runtime.GOMAXPROCS(0)
+	if workers > attempts { workers = attempts }
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i   int
+		g   Grid
+		err error
+	}
+	results := make(chan result, workers)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= attempts || ctx.Err() != nil {
+					return
+				}
+				g, err := generateAttempt(ctx, p, attemptSeed(seed, i))
+				select {
+				case results <- result{i, g, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	errs := make([]error, attempts)
+	found := make(map[int]Grid)
+	lowest := 0 // every attempt below lowest has failed
+	for r := range results {
+		if r.err != nil {
+			if ctx.Err() != nil {
+				continue // aborted, not a real failure
+			}
+			errs[r.i] = r.err
+		} else {
+			if seed == "" {
+				return r.g, nil
+			}
+			found[r.i] = r.g
+		}
+		for lowest < attempts {
+			if g, ok := found[lowest]; ok {
+				return g, nil
+			}
+			if errs[lowest] == nil {
+				break
+			}
+			lowest++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Grid{}, err
+	}
+	// every attempt failed; report the last one like the sequential loop
+	return Grid{}, errs[attempts-1]
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// However the attempts are spread over workers, a seed gives the puzzle of
+// the sequential loop.
+func TestGenerateContextMatchesSequential(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 4} {
+		runtime.GOMAXPROCS(procs)
+		for _, d := range []Difficulty{Normal, Hard, Lunatic} {
+			p := ParamsFor(d)
+			p.Symmetry = Rotational
+			for i := 0; i < 3; i++ {
+				seed := fmt.Sprintf("par/%d", i)
+				want, werr := GenerateWithParams(p, seed)
+				got, gerr := GenerateContext(context.Background(), p, seed)
+				if got != want || (werr == nil) != (gerr == nil) {
+					t.Errorf("GOMAXPROCS=%d %v %s: parallel %v, sequential %v", procs, d, seed, gerr, werr)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateContextCancel(t *testing.T) {
+	p := ParamsFor(Lunatic)
+	// an impossible band keeps every attempt failing, so only ctx ends it
+	p.MinTechnique, p.MaxTechnique, p.MaxAttempts = 1, 0, 1<<20
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := GenerateContext(ctx, p, "cancel")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("returned %v after the cancel", d)
+	}
+
+	done, stop := context.WithCancel(context.Background())
+	stop()
+	if _, err := GenerateContext(done, ParamsFor(Easy), "x"); !errors.Is(err, context.Canceled) {
+		t.Errorf("already cancelled: err = %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package generator
+
+import "time"
+
+// rng is a small xoshiro256** generator seeded through splitmix64. It is
+// implemented here rather than taken from math/rand so seeded puzzles stay
+// bit-identical across Go releases and platforms; Version pins the rest.
+type rng struct {
+	s [4]uint64
+}
+
+// newRNG seeds from a seed string; "" seeds from the clock (unseeded games).
+func newRNG(seed string, stream uint64) *rng {
+	x := hashStringToUint64(seed)
+	if seed == "" {
+		x = uint64(time.Now().UnixNano())
+	}
+	x += stream
+	r := &rng{}
+	for i := range r.s {
+		// splitmix64
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		r.s[i] = z ^ (z >> 31)
+	}
+	return r
+}
+
+func rotl(x uint64, k uint) uint64 { return (x << k) | (x >> (64 - k)) }
+
+func (r *rng) uint64() uint64 {
+	s := &r.s
+	out := rotl(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = rotl(s[3], 45)
+	return out
+}
+
+// intn returns a uniform value in [0,n) without modulo bias.
+func (r *rng) intn(n int) int {
+	bound := uint64(n)
+	limit := -bound % bound // 2^64 mod n
+	for {
+		v := r.uint64()
+		if v >= limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// shuffle is a Fisher–Yates shuffle with a fixed draw order.
+func (r *rng) shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.intn(i+1))
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symmetry is the pattern the clues of a carved puzzle follow. Cells are
+// removed in orbits (a cell with its mirror images), so the givens keep the
+// shape while uniqueness is still checked for every removal.
+type Symmetry int
+
+const (
+	NoSymmetry Symmetry = iota
+	// Rotational is 180° point symmetry around the center, the classic layout.
+	Rotational
+	// Diagonal mirrors across the main (top-left to bottom-right) diagonal.
+	Diagonal
+	// Mirror mirrors left to right.
+	Mirror
+	// FourFold mirrors both left to right and top to bottom.
+	FourFold
+)
+
+// String returns the name used in the menu and config.
+func (s Symmetry) String() string {
+	switch s {
+	case Rotational:
+		return "Rotational"
+	case Diagonal:
+		return "Diagonal"
+	case Mirror:
+		return "Mirror"
+	case FourFold:
+		return "Four-fold"
+	}
+	return "None"
+}
+
+// ParseSymmetry maps a case-insensitive name (none|rotational|diagonal|mirror|four-fold) to a Symmetry.
+func ParseSymmetry(s string) (Symmetry, error) {
+	for sym := NoSymmetry; sym <= FourFold; sym++ {
+		if strings.EqualFold(s, sym.String()) {
+			return sym, nil
+		}
+	}
+	if strings.EqualFold(s, "fourfold") {
+		return FourFold, nil
+	}
+	return NoSymmetry, fmt.Errorf("unknown symmetry %q (want none|rotational|diagonal|mirror|four-fold)", s)
+}
+
+// orbits partitions the 81 cell indexes into groups removed together, each
+// listed once in index order of its first cell. With NoSymmetry every cell is
+// its own orbit, which keeps carving identical to the plain shuffled order.
+func orbits(s Symmetry) [][]int {
+	var out [][]int
+	var seen [81]bool
+	for i := 0; i < 81; i++ {
+		if seen[i] { continue }
+		r, c := i/9, i%9
+		var cells [][2]int
+		switch s {
+		case Rotational:
+			cells = [][2]int{{r, c}, {8 - r, 8 - c}}
+		case Diagonal:
+			cells = [][2]int{{r, c}, {c, r}}
+		case Mirror:
+			cells = [][2]int{{r, c}, {r, 8 - c}}
+		case FourFold:
+			cells = [][2]int{{r, c}, {r, 8 - c}, {8 - r, c}, {8 - r, 8 - c}}
+		default:
+			cells = [][2]int{{r, c}}
+		}
+		var orbit []int
+		for _, rc := range cells {
+			idx := rc[0]*9 + rc[1]
+			if !seen[idx] {
+				seen[idx] = true
+				orbit = append(orbit, idx)
+			}
+		}
+		out = append(out, orbit)
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"punkdoku/internal/game"
+)
+
+// images lists (r, c) with its mirror images under s.
+func images(s Symmetry, r, c int) [][2]int {
+	switch s {
+	case Rotational:
+		return [][2]int{{r, c}, {8 - r, 8 - c}}
+	case Diagonal:
+		return [][2]int{{r, c}, {c, r}}
+	case Mirror:
+		return [][2]int{{r, c}, {r, 8 - c}}
+	case FourFold:
+		return [][2]int{{r, c}, {r, 8 - c}, {8 - r, c}, {8 - r, 8 - c}}
+	}
+	return [][2]int{{r, c}}
+}
+
+func TestOrbitsPartition(t *testing.T) {
+	for sym := NoSymmetry; sym <= FourFold; sym++ {
+		var orbitOf [81]int
+		for i := range orbitOf {
+			orbitOf[i] = -1
+		}
+		for k, orbit := range orbits(sym) {
+			if len(orbit) == 0 {
+				t.Errorf("%v: orbit %d is empty", sym, k)
+			}
+			for _, idx := range orbit {
+				if idx < 0 || idx >= 81 {
+					t.Fatalf("%v: cell %d out of range", sym, idx)
+				}
+				if orbitOf[idx] >= 0 {
+					t.Errorf("%v: cell %d in orbits %d and %d", sym, idx, orbitOf[idx], k)
+				}
+				orbitOf[idx] = k
+			}
+		}
+		for idx, k := range orbitOf {
+			if k < 0 {
+				t.Errorf("%v: cell %d in no orbit", sym, idx)
+				continue
+			}
+			// an orbit is closed under the symmetry: every image shares it
+			for _, p := range images(sym, idx/9, idx%9) {
+				if orbitOf[p[0]*9+p[1]] != k {
+					t.Errorf("%v: r%dc%d and its image r%dc%d in different orbits", sym, idx/9+1, idx%9+1, p[0]+1, p[1]+1)
+				}
+			}
+		}
+	}
+}
+
+func TestCarvedSymmetric(t *testing.T) {
+	for sym := Rotational; sym <= FourFold; sym++ {
+		for _, d := range []Difficulty{Easy, Hard, Minimal} {
+			for _, v := range []game.Variant{game.Classic, game.XSudoku} {
+				p := ParamsFor(d)
+				p.Symmetry = sym
+				p.Variant = v
+				seed := fmt.Sprintf("sym/%v/%v/%v", sym, d, v)
+				g, err := GenerateWithParams(p, seed)
+				if err != nil {
+					t.Errorf("%s: %v", seed, err)
+					continue
+				}
+				for r := 0; r < 9; r++ {
+					for c := 0; c < 9; c++ {
+						for _, q := range images(sym, r, c) {
+							if (g[r][c] == 0) != (g[q[0]][q[1]] == 0) {
+								t.Errorf("%s: r%dc%d and its image r%dc%d differ\n%s", seed, r+1, c+1, q[0]+1, q[1]+1, game.Grid(g))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
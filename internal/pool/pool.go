@@ -0,0 +1,198 @@
+// Package pool keeps a few ready-made puzzles per difficulty so starting a
+// game from the menu doesn't wait for the generator. Puzzles are produced by
+// generator.GenerateContext with a recorded seed, so a pooled game is
+// exactly the one its seed (and share code) describes.
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+)
+
+// Entry is one pre-generated puzzle.
+type Entry struct {
+	Seed   string         `json:"seed"`
+	Puzzle string         `json:"puzzle"`
+	Grid   generator.Grid `json:"-"`
+}
+
+// file is the on-disk cache; puzzles are dropped when Version changes, since
+// their seeds would no longer reproduce them.
+type file struct {
+	Version int                `json:"version"`
+	Puzzles map[string][]Entry `json:"puzzles"`
+}
+
+// Pool is safe for concurrent use.
+type Pool struct {
+	size int
+
+	mu      sync.Mutex
+	puzzles map[string][]Entry
+	// filling maps a key to the fill generation topping it up; only the
+	// current generation (gen) counts, older ones are being cancelled.
+	filling map[string]uint64
+	gen     uint64
+	sym     generator.Symmetry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	// diskMu serialises cache writes from the fill goroutines
+	diskMu sync.Mutex
+}
+
+var seq atomic.Uint64
+
+// New loads the cache and keeps up to size puzzles per difficulty and symmetry.
+func New(size int) *Pool {
+	p := &Pool{size: size, puzzles: map[string][]Entry{}, filling: map[string]uint64{}}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.cancel() // nothing fills until the first Fill
+	f, err := load()
+	if err != nil || f.Version != generator.Version { return p }
+	for k, es := range f.Puzzles {
+		for _, e := range es {
+			g, err := game.ParseGrid(e.Puzzle)
+			if err != nil { continue }
+			e.Grid = generator.Grid(g)
+			p.puzzles[k] = append(p.puzzles[k], e)
+		}
+	}
+	return p
+}
+
+func key(d generator.Difficulty, sym generator.Symmetry) string {
+	return d.String() + "/" + sym.String()
+}
+
+// Take pops a ready puzzle and, when sym is the symmetry being filled, tops
+// that difficulty up again in the background.
+func (p *Pool) Take(d generator.Difficulty, sym generator.Symmetry) (Entry, bool) {
+	k := key(d, sym)
+	p.mu.Lock()
+	es := p.puzzles[k]
+	if len(es) == 0 {
+		p.mu.Unlock()
+		p.fill(d, sym)
+		return Entry{}, false
+	}
+	e := es[0]
+	p.puzzles[k] = es[1:]
+	p.mu.Unlock()
+	p.persist()
+	p.fill(d, sym)
+	return e, true
+}
+
+// Fill tops every difficulty up to size for sym, one background goroutine per
+// difficulty, and returns at once. Only one symmetry fills at a time: a new
+// sym cancels the fill of the previous one, as does cancelling ctx or Close.
+// Calling it again for the symmetry already filling keeps that fill going.
+func (p *Pool) Fill(ctx context.Context, sym generator.Symmetry) {
+	p.mu.Lock()
+	if sym != p.sym || p.ctx.Err() != nil {
+		p.cancel()
+		p.ctx, p.cancel = context.WithCancel(ctx)
+		p.sym = sym
+		p.gen++
+	}
+	p.mu.Unlock()
+	for d := generator.Easy; d <= generator.Minimal; d++ {
+		p.fill(d, sym)
+	}
+}
+
+// Close cancels the running fill and waits for its goroutines, so nothing
+// writes the cache after it returns.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.cancel()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *Pool) fill(d generator.Difficulty, sym generator.Symmetry) {
+	k := key(d, sym)
+	p.mu.Lock()
+	if sym != p.sym || p.ctx.Err() != nil || p.filling[k] == p.gen || len(p.puzzles[k]) >= p.size {
+		p.mu.Unlock()
+		return
+	}
+	gen, ctx := p.gen, p.ctx
+	p.filling[k] = gen
+	p.wg.Add(1)
+	p.mu.Unlock()
+
+	go func() {
+		defer p.wg.Done()
+		params := generator.ParamsFor(d)
+		params.Symmetry = sym
+		failures := 0
+		for {
+			p.mu.Lock()
+			done := len(p.puzzles[k]) >= p.size || failures >= 3 || ctx.Err() != nil
+			if done && p.filling[k] == gen { delete(p.filling, k) }
+			p.mu.Unlock()
+			if done { return }
+
+			seed := newSeed()
+			g, err := generator.GenerateContext(ctx, params, seed)
+			if err != nil {
+				failures++ // the menu falls back to generating on demand
+				continue
+			}
+			p.mu.Lock()
+			p.puzzles[k] = append(p.puzzles[k], Entry{Seed: seed, Puzzle: game.Grid(g).String(), Grid: g})
+			p.mu.Unlock()
+			p.persist()
+		}
+	}()
+}
+
+// newSeed matches the menu's random seeds; the counter keeps goroutines that
+// start in the same nanosecond apart.
+func newSeed() string {
+	return strconv.FormatInt(time.Now().UnixNano()+int64(seq.Add(1)), 36)
+}
+
+func path() (string, error) {
+	h, err := os.UserHomeDir()
+	if err != nil { return "", err }
+	return filepath.Join(h, ".punkdoku", "cache", "pool.json"), nil
+}
+
+func load() (file, error) {
+	var f file
+	p, err := path()
+	if err != nil { return f, err }
+	b, err := os.ReadFile(p)
+	if err != nil { return f, err }
+	err = json.Unmarshal(b, &f)
+	return f, err
+}
+
+// persist writes the pool to the cache; failures only cost the next start its head start.
+func (p *Pool) persist() {
+	p.diskMu.Lock()
+	defer p.diskMu.Unlock()
+	p.mu.Lock()
+	data, err := json.MarshalIndent(file{Version: generator.Version, Puzzles: p.puzzles}, "", "  ")
+	p.mu.Unlock()
+	if err != nil { return }
+	path, err := path()
+	if err != nil { return }
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { return }
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil { return }
+	_ = os.Rename(tmp, path)
+}
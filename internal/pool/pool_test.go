@@ -0,0 +1,125 @@
+package pool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"punkdoku/internal/generator"
+)
+
+func ready(p *Pool, d generator.Difficulty, sym generator.Symmetry) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.puzzles[key(d, sym)])
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(20 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTakeRefills(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p := New(1)
+	defer p.Close()
+	if _, ok := p.Take(generator.Easy, generator.NoSymmetry); ok {
+		t.Fatal("took a puzzle from an empty pool")
+	}
+	p.Fill(context.Background(), generator.NoSymmetry)
+	waitFor(t, "the first Easy puzzle", func() bool { return ready(p, generator.Easy, generator.NoSymmetry) == 1 })
+
+	e, ok := p.Take(generator.Easy, generator.NoSymmetry)
+	if !ok {
+		t.Fatal("no puzzle after the fill")
+	}
+	// the seed reproduces the pooled puzzle, so its share code is right
+	if g, err := generator.GenerateWithParams(generator.ParamsFor(generator.Easy), e.Seed); err != nil || g != e.Grid {
+		t.Errorf("seed %s gives another puzzle (%v)", e.Seed, err)
+	}
+	waitFor(t, "the refill", func() bool { return ready(p, generator.Easy, generator.NoSymmetry) == 1 })
+
+	// what was generated survives a restart
+	p.Close()
+	if q := New(1); ready(q, generator.Easy, generator.NoSymmetry) != 1 {
+		t.Error("cache not reloaded")
+	}
+}
+
+// A key fills in one goroutine at a time, and Fill for the symmetry already
+// filling doesn't restart it.
+func TestFillDedupe(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p := New(3)
+	defer p.Close()
+	p.Fill(context.Background(), generator.Mirror)
+	p.mu.Lock()
+	gen, n := p.gen, len(p.filling)
+	p.mu.Unlock()
+	p.Fill(context.Background(), generator.Mirror)
+	p.fill(generator.Hard, generator.Mirror)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.gen != gen {
+		t.Errorf("second Fill restarted the fill")
+	}
+	if len(p.filling) > n || n > int(generator.Minimal)+1 {
+		t.Errorf("%d keys filling after one Fill, %d after repeats", n, len(p.filling))
+	}
+	for k, g := range p.filling {
+		if g != gen {
+			t.Errorf("%s filled by generation %d, want %d", k, g, gen)
+		}
+	}
+}
+
+func TestFillCancel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p := New(50)
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Fill(ctx, generator.NoSymmetry)
+
+	// a new symmetry cancels the old fill and leaves the new one running
+	p.Fill(ctx, generator.FourFold)
+	p.mu.Lock()
+	for k, g := range p.filling {
+		if !strings.HasSuffix(k, "/Four-fold") && g == p.gen {
+			t.Errorf("%s still counts as filling", k)
+		}
+	}
+	p.mu.Unlock()
+	p.fill(generator.Hard, generator.NoSymmetry)
+	p.mu.Lock()
+	if p.filling[key(generator.Hard, generator.NoSymmetry)] == p.gen {
+		t.Error("an inactive symmetry started filling")
+	}
+	p.mu.Unlock()
+
+	cancel()
+	done := make(chan struct{})
+	go func() { p.Close(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fill goroutines kept running after cancel")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.filling) != 0 {
+		t.Errorf("still filling %v after Close", p.filling)
+	}
+	n := len(p.puzzles[key(generator.Easy, generator.FourFold)])
+	p.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	p.mu.Lock()
+	if m := len(p.puzzles[key(generator.Easy, generator.FourFold)]); m != n {
+		t.Errorf("puzzles added after Close: %d -> %d", n, m)
+	}
+}
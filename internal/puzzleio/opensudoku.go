@@ -0,0 +1,78 @@
+package puzzleio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"punkdoku/internal/game"
+)
+
+// openSudokuDoc covers the OpenSudoku collection layout: a <name>,
+// <author> and <description> followed by <game data="81 digits"/> entries,
+// optionally grouped in <folder> elements.
+type openSudokuDoc struct {
+	XMLName     xml.Name         `xml:"opensudoku"`
+	Name        string           `xml:"name,omitempty"`
+	Author      string           `xml:"author,omitempty"`
+	Description string           `xml:"description,omitempty"`
+	Games       []openSudokuGame `xml:"game"`
+	Folders     []struct {
+		Name  string           `xml:"name,attr"`
+		Games []openSudokuGame `xml:"game"`
+	} `xml:"folder"`
+}
+
+type openSudokuGame struct {
+	Data string `xml:"data,attr"`
+}
+
+func readOpenSudoku(data []byte) ([]Puzzle, error) {
+	var doc openSudokuDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("opensudoku: %w", err)
+	}
+	var out []Puzzle
+	add := func(name string, games []openSudokuGame) error {
+		for i, gm := range games {
+			g, err := game.ParseGrid(strings.TrimSpace(gm.Data))
+			if err != nil {
+				return fmt.Errorf("opensudoku: game %d: %w", len(out)+i+1, err)
+			}
+			out = append(out, Puzzle{Name: name, Author: doc.Author, Description: doc.Description, Board: game.NewBoardFromPuzzle(g)})
+		}
+		return nil
+	}
+	if err := add(doc.Name, doc.Games); err != nil {
+		return nil, err
+	}
+	for _, f := range doc.Folders {
+		if err := add(f.Name, f.Games); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func writeOpenSudoku(b *bytes.Buffer, ps []Puzzle) error {
+	doc := openSudokuDoc{Name: "punkdoku"}
+	if len(ps) > 0 {
+		if ps[0].Name != "" {
+			doc.Name = ps[0].Name
+		}
+		doc.Author = ps[0].Author
+		doc.Description = ps[0].Description
+	}
+	for _, p := range ps {
+		doc.Games = append(doc.Games, openSudokuGame{Data: strings.ReplaceAll(game.Grid(p.Grid()).String(), ".", "0")})
+	}
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("opensudoku: %w", err)
+	}
+	b.WriteByte('\n')
+	return nil
+}
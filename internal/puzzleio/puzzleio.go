@@ -0,0 +1,441 @@
+// Package puzzleio reads and writes Sudoku interchange formats so puzzles and
+// games in progress can move between punkdoku and other tools.
+package puzzleio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+)
+
+// Format identifies a file format.
+type Format int
+
+const (
+	// Line holds one 81-character puzzle per line ('.' or '0' = blank).
+	Line Format = iota
+	// SDK is SadMan Software's single-puzzle grid: nine rows of nine cells.
+	SDK
+	// SDX is SadMan Software's grid with solved cells and pencil marks.
+	SDX
+	// SS is Simple Sudoku's grid with '|' and '-' box separators.
+	SS
+	// OpenSudoku is the OpenSudoku XML collection format.
+	OpenSudoku
+)
+
+func (f Format) String() string {
+	switch f {
+	case Line:
+		return "line"
+	case SDK:
+		return "sdk"
+	case SDX:
+		return "sdx"
+	case SS:
+		return "ss"
+	case OpenSudoku:
+		return "opensudoku"
+	}
+	return "unknown"
+}
+
+// ParseFormat maps a format name (as printed by String) to a Format.
+func ParseFormat(s string) (Format, error) {
+	for f := Line; f <= OpenSudoku; f++ {
+		if strings.EqualFold(s, f.String()) {
+			return f, nil
+		}
+	}
+	return Line, fmt.Errorf("unknown puzzle format %q (want line|sdk|sdx|ss|opensudoku)", s)
+}
+
+// FormatForPath guesses the format from a file extension; .txt and unknown
+// extensions are treated as Line.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sdk":
+		return SDK
+	case ".sdx":
+		return SDX
+	case ".ss":
+		return SS
+	case ".xml", ".opensudoku":
+		return OpenSudoku
+	}
+	return Line
+}
+
+// ErrSinglePuzzle is returned when writing several puzzles to a format that holds only one.
+var ErrSinglePuzzle = errors.New("format holds a single puzzle")
+
+// Puzzle is one puzzle read from or written to a file. Board carries the
+// givens plus, for formats that have them, entered values and pencil marks.
+type Puzzle struct {
+	Name        string
+	Author      string
+	Description string
+	Board       game.Board
+}
+
+// FromGrid wraps a fresh puzzle with no progress.
+func FromGrid(g generator.Grid) Puzzle {
+	return Puzzle{Board: game.NewBoardFromPuzzle(game.Grid(g))}
+}
+
+// FromBoard wraps a game in progress.
+func FromBoard(b game.Board) Puzzle {
+	return Puzzle{Board: b}
+}
+
+// Grid returns only the givens, as the generator represents a puzzle.
+func (p Puzzle) Grid() generator.Grid {
+	var g generator.Grid
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if p.Board.Given[r][c] {
+				g[r][c] = p.Board.Values[r][c]
+			}
+		}
+	}
+	return g
+}
+
+// Read parses every puzzle in r.
+func Read(r io.Reader, f Format) ([]Puzzle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case SDK:
+		return readSDK(string(data))
+	case SDX:
+		return readSDX(string(data))
+	case SS:
+		return readSS(string(data))
+	case OpenSudoku:
+		return readOpenSudoku(data)
+	}
+	return readLines(string(data))
+}
+
+// Write encodes ps to w. SDK, SDX and SS hold exactly one puzzle.
+func Write(w io.Writer, f Format, ps []Puzzle) error {
+	if f == SDK || f == SDX || f == SS {
+		if len(ps) != 1 {
+			return fmt.Errorf("%s: %w, got %d", f, ErrSinglePuzzle, len(ps))
+		}
+	}
+	var b bytes.Buffer
+	switch f {
+	case SDK:
+		writeSDK(&b, ps[0])
+	case SDX:
+		writeSDX(&b, ps[0])
+	case SS:
+		writeSS(&b, ps[0])
+	case OpenSudoku:
+		if err := writeOpenSudoku(&b, ps); err != nil {
+			return err
+		}
+	default:
+		for _, p := range ps {
+			b.WriteString(game.Grid(p.Grid()).String())
+			b.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// ReadFile reads path in the format implied by its extension. A Line file
+// that turns out to be a single 9-row grid is read as SDK.
+func ReadFile(path string) ([]Puzzle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	format := FormatForPath(path)
+	ps, err := Read(bytes.NewReader(data), format)
+	if err != nil && format == Line {
+		// a plain text file may hold one 9x9 grid rather than 81-char lines
+		if alt, altErr := Read(bytes.NewReader(data), SDK); altErr == nil {
+			return alt, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ps, nil
+}
+
+// WriteFile writes ps to path in the format implied by its extension.
+func WriteFile(path string, ps []Puzzle) error {
+	var b bytes.Buffer
+	if err := Write(&b, FormatForPath(path), ps); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// ---- line ------------------------------------------------------------------
+
+func readLines(data string) ([]Puzzle, error) {
+	var out []Puzzle
+	for i, l := range strings.Split(data, "\n") {
+		l = stripComment(l)
+		if l == "" {
+			continue
+		}
+		g, err := game.ParseGrid(l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		out = append(out, Puzzle{Board: game.NewBoardFromPuzzle(g)})
+	}
+	return out, nil
+}
+
+func stripComment(l string) string {
+	if i := strings.IndexByte(l, '#'); i >= 0 {
+		l = l[:i]
+	}
+	return strings.TrimSpace(l)
+}
+
+// ---- SadMan .sdk -----------------------------------------------------------
+
+// readSDK reads nine rows of nine cells. Header lines start with '#' and a
+// letter: #A author, #D description, #N name (others are ignored); a
+// "[Puzzle]" section marker is skipped.
+func readSDK(data string) ([]Puzzle, error) {
+	var p Puzzle
+	var rows []string
+	for _, l := range strings.Split(data, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case l == "" || strings.HasPrefix(l, "["):
+			continue
+		case strings.HasPrefix(l, "#"):
+			readHeader(&p, l)
+			continue
+		}
+		rows = append(rows, l)
+	}
+	if len(rows) < 9 {
+		return nil, fmt.Errorf("sdk: expected 9 rows, got %d", len(rows))
+	}
+	g, err := game.ParseGrid(strings.Join(rows[:9], ""))
+	if err != nil {
+		return nil, fmt.Errorf("sdk: %w", err)
+	}
+	p.Board = game.NewBoardFromPuzzle(g)
+	return []Puzzle{p}, nil
+}
+
+func readHeader(p *Puzzle, l string) {
+	if len(l) < 2 {
+		return
+	}
+	v := strings.TrimSpace(l[2:])
+	switch l[1] {
+	case 'A':
+		p.Author = v
+	case 'D':
+		p.Description = v
+	case 'N':
+		p.Name = v
+	}
+}
+
+func writeHeader(b *bytes.Buffer, p Puzzle) {
+	if p.Name != "" {
+		fmt.Fprintf(b, "#N %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(b, "#A %s\n", p.Author)
+	}
+	if p.Description != "" {
+		fmt.Fprintf(b, "#D %s\n", p.Description)
+	}
+}
+
+func writeSDK(b *bytes.Buffer, p Puzzle) {
+	writeHeader(b, p)
+	line := game.Grid(p.Grid()).String()
+	for r := 0; r < 9; r++ {
+		b.WriteString(line[r*9 : r*9+9])
+		b.WriteByte('\n')
+	}
+}
+
+// ---- SadMan .sdx -----------------------------------------------------------
+
+// readSDX reads nine rows of nine space-separated tokens:
+//   - "5"      a given
+//   - "u5"     a value entered by the player
+//   - "1389"   pencil marks (all nine digits means no marks)
+//   - "c5"     a single pencil mark (punkdoku's spelling, since a lone
+//     digit already means a given)
+//   - "0" / "." an empty cell
+func readSDX(data string) ([]Puzzle, error) {
+	var p Puzzle
+	row := 0
+	for _, l := range strings.Split(data, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "[") {
+			continue
+		}
+		if strings.HasPrefix(l, "#") {
+			readHeader(&p, l)
+			continue
+		}
+		if row >= 9 {
+			break
+		}
+		toks := strings.Fields(l)
+		if len(toks) != 9 {
+			return nil, fmt.Errorf("sdx: row %d: expected 9 cells, got %d", row+1, len(toks))
+		}
+		for c, t := range toks {
+			if err := readSDXCell(&p.Board, row, c, t); err != nil {
+				return nil, fmt.Errorf("sdx: r%dc%d: %w", row+1, c+1, err)
+			}
+		}
+		row++
+	}
+	if row != 9 {
+		return nil, fmt.Errorf("sdx: expected 9 rows, got %d", row)
+	}
+	return []Puzzle{p}, nil
+}
+
+func readSDXCell(b *game.Board, r, c int, t string) error {
+	digits := func(s string) (uint16, error) {
+		var m uint16
+		for _, ch := range s {
+			if ch < '1' || ch > '9' {
+				return 0, fmt.Errorf("invalid cell %q", t)
+			}
+			m |= game.NoteBit(uint8(ch - '0'))
+		}
+		return m, nil
+	}
+	switch {
+	case t == "0" || t == ".":
+		return nil
+	case strings.HasPrefix(t, "u") && len(t) == 2:
+		m, err := digits(t[1:])
+		if err != nil {
+			return err
+		}
+		b.Values[r][c] = maskDigit(m)
+	case strings.HasPrefix(t, "c"):
+		m, err := digits(t[1:])
+		if err != nil {
+			return err
+		}
+		b.Notes[r][c] = m
+	case len(t) == 1:
+		m, err := digits(t)
+		if err != nil {
+			return err
+		}
+		b.Values[r][c] = maskDigit(m)
+		b.Given[r][c] = true
+	default:
+		m, err := digits(t)
+		if err != nil {
+			return err
+		}
+		if m != 0x1ff {
+			b.Notes[r][c] = m
+		}
+	}
+	return nil
+}
+
+func maskDigit(m uint16) uint8 {
+	for v := uint8(1); v <= 9; v++ {
+		if m == game.NoteBit(v) {
+			return v
+		}
+	}
+	return 0
+}
+
+func writeSDX(b *bytes.Buffer, p Puzzle) {
+	writeHeader(b, p)
+	for r := 0; r < 9; r++ {
+		toks := make([]string, 9)
+		for c := 0; c < 9; c++ {
+			v := p.Board.Values[r][c]
+			notes := p.Board.Notes[r][c]
+			switch {
+			case p.Board.Given[r][c]:
+				toks[c] = fmt.Sprint(v)
+			case v != 0:
+				toks[c] = fmt.Sprintf("u%d", v)
+			case notes == 0:
+				toks[c] = "0"
+			case maskDigit(notes) != 0:
+				toks[c] = fmt.Sprintf("c%d", maskDigit(notes))
+			default:
+				var sb strings.Builder
+				for d := uint8(1); d <= 9; d++ {
+					if notes&game.NoteBit(d) != 0 {
+						sb.WriteByte('0' + d)
+					}
+				}
+				toks[c] = sb.String()
+			}
+		}
+		b.WriteString(strings.Join(toks, " "))
+		b.WriteByte('\n')
+	}
+}
+
+// ---- Simple Sudoku .ss -----------------------------------------------------
+
+// readSS reads the Simple Sudoku layout: rows like "..3|.1.|..." with
+// "-----------" separator lines. 'X' and 'x' are accepted as blanks.
+func readSS(data string) ([]Puzzle, error) {
+	var cells strings.Builder
+	for _, l := range strings.Split(data, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "-") || strings.HasPrefix(l, "#") {
+			continue
+		}
+		l = strings.NewReplacer("|", "", "X", ".", "x", ".", "*", "").Replace(l)
+		cells.WriteString(l)
+	}
+	g, err := game.ParseGrid(cells.String())
+	if err != nil {
+		return nil, fmt.Errorf("ss: %w", err)
+	}
+	return []Puzzle{{Board: game.NewBoardFromPuzzle(g)}}, nil
+}
+
+func writeSS(b *bytes.Buffer, p Puzzle) {
+	line := game.Grid(p.Grid()).String()
+	for r := 0; r < 9; r++ {
+		if r == 3 || r == 6 {
+			b.WriteString("-----------\n")
+		}
+		row := line[r*9 : r*9+9]
+		fmt.Fprintf(b, "%s|%s|%s\n", row[0:3], row[3:6], row[6:9])
+	}
+}
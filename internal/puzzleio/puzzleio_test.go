@@ -0,0 +1,117 @@
+package puzzleio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"punkdoku/internal/game"
+)
+
+const testPuzzle = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+
+func mustBoard(t *testing.T, s string) game.Board {
+	t.Helper()
+	g, err := game.ParseGrid(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game.NewBoardFromPuzzle(g)
+}
+
+func TestRoundTrip(t *testing.T) {
+	b := mustBoard(t, testPuzzle)
+	p := Puzzle{Name: "Easy one", Author: "punk", Description: "first of the book", Board: b}
+	second := Puzzle{Board: mustBoard(t, strings.Replace(testPuzzle, "..3", "4.3", 1))}
+	for f := Line; f <= OpenSudoku; f++ {
+		ps := []Puzzle{p}
+		if f == Line || f == OpenSudoku {
+			ps = append(ps, second)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, f, ps); err != nil {
+			t.Fatalf("%v: write: %v", f, err)
+		}
+		got, err := Read(&buf, f)
+		if err != nil {
+			t.Fatalf("%v: read: %v", f, err)
+		}
+		if len(got) != len(ps) {
+			t.Fatalf("%v: read %d puzzles, wrote %d", f, len(got), len(ps))
+		}
+		for i := range ps {
+			if got[i].Grid() != ps[i].Grid() {
+				t.Errorf("%v: puzzle %d came back as %s", f, i, game.Grid(got[i].Grid()))
+			}
+		}
+		if (f == SDK || f == SDX) && (got[0].Name != p.Name || got[0].Author != p.Author || got[0].Description != p.Description) {
+			t.Errorf("%v: header came back as %q/%q/%q", f, got[0].Name, got[0].Author, got[0].Description)
+		}
+	}
+}
+
+// SDX keeps a game in progress: entered values and pencil marks survive.
+func TestSDXProgress(t *testing.T) {
+	b := mustBoard(t, testPuzzle)
+	b.Values[0][0] = 4
+	b.Notes[0][1] = game.NoteBit(5) | game.NoteBit(8)
+	b.Notes[0][3] = game.NoteBit(7)
+	var buf bytes.Buffer
+	if err := Write(&buf, SDX, []Puzzle{FromBoard(b)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(&buf, SDX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := got[0].Board
+	if g.Values != b.Values || g.Given != b.Given || g.Notes != b.Notes {
+		t.Errorf("board changed in the round trip:\n%s", buf.String())
+	}
+}
+
+func TestSingleFormatsRejectSeveral(t *testing.T) {
+	ps := []Puzzle{FromBoard(mustBoard(t, testPuzzle)), FromBoard(mustBoard(t, testPuzzle))}
+	for _, f := range []Format{SDK, SDX, SS} {
+		if err := Write(&bytes.Buffer{}, f, ps); err == nil {
+			t.Errorf("%v: wrote two puzzles", f)
+		}
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	rows := func(n int, row string) string { return strings.Repeat(row+"\n", n) }
+	tests := []struct {
+		name string
+		f    Format
+		in   string
+	}{
+		{"short line", Line, testPuzzle[:80]},
+		{"bad character", Line, strings.Replace(testPuzzle, "3", "z", 1)},
+		{"sdk short rows", SDK, rows(8, "..3.2.6..")},
+		{"sdk bad character", SDK, rows(8, "..3.2.6..") + "..3.?.6..\n"},
+		{"sdx short row", SDX, rows(8, "0 0 3 0 2 0 6 0 0") + "0 0 3 0 2 0 6 0\n"},
+		{"sdx missing rows", SDX, rows(3, "0 0 3 0 2 0 6 0 0")},
+		{"sdx bad cell", SDX, rows(8, "0 0 3 0 2 0 6 0 0") + "0 0 3 0 2 0 6 0 u0\n"},
+		{"ss short", SS, "..3|.2.|6..\n"},
+		{"ss bad character", SS, rows(9, "..3|.2.|6.q")},
+		{"empty xml", OpenSudoku, ""},
+		{"broken xml", OpenSudoku, "<opensudoku><game data="},
+		{"xml bad game", OpenSudoku, `<opensudoku><game data="123"/></opensudoku>`},
+	}
+	for _, tt := range tests {
+		if ps, err := Read(strings.NewReader(tt.in), tt.f); err == nil {
+			t.Errorf("%s: read %d puzzles, want an error", tt.name, len(ps))
+		}
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	for path, want := range map[string]Format{
+		"a.txt": Line, "b.SDK": SDK, "c.sdx": SDX, "d.ss": SS, "e.xml": OpenSudoku, "f.opensudoku": OpenSudoku, "g": Line,
+	} {
+		if got := FormatForPath(path); got != want {
+			t.Errorf("FormatForPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
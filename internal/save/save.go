@@ -0,0 +1,117 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"punkdoku/internal/game"
+)
+
+// Game is the on-disk snapshot of an in-progress puzzle.
+type Game struct {
+	Difficulty string        `json:"difficulty"`
+	Seed       string        `json:"seed"`
+	// Symmetry is the generator's clue layout, needed to rebuild the share code.
+	Symmetry   string        `json:"symmetry,omitempty"`
+	// Variant names the extra regions (game.Variant), empty for classic.
+	Variant    string        `json:"variant,omitempty"`
+	Puzzle     game.Grid     `json:"puzzle"`
+	Solution   game.Grid     `json:"solution"`
+	Given      [9][9]bool    `json:"given"`
+	Values     game.Grid     `json:"values"`
+	Notes      game.Notes    `json:"notes"`
+	// Cages is set for Killer games.
+	Cages      []game.Cage   `json:"cages,omitempty"`
+	Undo       []game.Move   `json:"undo"`
+	Redo       []game.Move   `json:"redo"`
+	Elapsed    time.Duration `json:"elapsed"`
+	HintsUsed  int           `json:"hintsUsed"`
+	Mistakes   int           `json:"mistakes"`
+	SavedAt    time.Time     `json:"savedAt"`
+}
+
+// Board rebuilds the playable board from the snapshot.
+func (g Game) Board() game.Board {
+	v, _ := game.ParseVariant(g.Variant)
+	return game.Board{Given: g.Given, Values: g.Values, Notes: g.Notes, Regions: v.Regions(), Cages: g.Cages}
+}
+
+// Started reports whether the player entered any digit of their own.
+func (g Game) Started() bool {
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if g.Values[r][c] != 0 && !g.Given[r][c] { return true }
+		}
+	}
+	return false
+}
+
+// Solved reports a full board that breaks no rule of its variant or cages.
+func (g Game) Solved() bool {
+	b := g.Board()
+	for _, row := range g.Values {
+		for _, v := range row {
+			if v == 0 { return false }
+		}
+	}
+	for _, row := range game.DuplicateMapAll(g.Values, b.Constraints()...) {
+		for _, bad := range row {
+			if bad { return false }
+		}
+	}
+	return true
+}
+
+func path() (string, error) {
+	h, err := os.UserHomeDir()
+	if err != nil { return "", err }
+	return filepath.Join(h, ".punkdoku", "save.json"), nil
+}
+
+// Exists reports whether a saved game is available to continue.
+func Exists() bool {
+	p, err := path()
+	if err != nil { return false }
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// Load reads the saved game. It returns an error wrapping fs.ErrNotExist when
+// nothing has been saved yet.
+func Load() (Game, error) {
+	var g Game
+	p, err := path()
+	if err != nil { return g, err }
+	b, err := os.ReadFile(p)
+	if err != nil { return g, err }
+	if err := json.Unmarshal(b, &g); err != nil { return g, err }
+	return g, nil
+}
+
+// Write replaces the saved game with g.
+func Write(g Game) error {
+	p, err := path()
+	if err != nil { return err }
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { return err }
+	g.SavedAt = time.Now()
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil { return err }
+	// 쓰는 도중 종료되어도 기존 저장본이 깨지지 않도록 임시 파일 후 rename
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil { return err }
+	return os.Rename(tmp, p)
+}
+
+// Clear deletes the saved game, if any.
+func Clear() error {
+	p, err := path()
+	if err != nil { return err }
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
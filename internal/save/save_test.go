@@ -0,0 +1,108 @@
+package save
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"time"
+
+	"punkdoku/internal/game"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if Exists() {
+		t.Fatal("fresh HOME has a save")
+	}
+	if _, err := Load(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Load on fresh HOME: %v, want fs.ErrNotExist", err)
+	}
+
+	var g Game
+	g.Difficulty = "hard"
+	g.Seed = "round-trip"
+	g.Symmetry = "diagonal"
+	g.Variant = "x-sudoku"
+	g.Puzzle[0][0], g.Solution[0][0], g.Given[0][0], g.Values[0][0] = 5, 5, true, 5
+	g.Solution[0][1], g.Values[0][1] = 3, 3
+	g.Notes[4][4] = game.NoteBit(2) | game.NoteBit(7)
+	g.Cages = []game.Cage{{Sum: 8, Cells: [][2]int{{0, 1}, {0, 2}}}}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	g.Undo = []game.Move{{Row: 0, Col: 1, Next: 3, PrevNotes: game.NoteBit(3), At: at,
+		Peers: []game.NoteEdit{{Row: 0, Col: 8, Prev: game.NoteBit(3) | game.NoteBit(4), Next: game.NoteBit(4)}}}}
+	g.Redo = []game.Move{{Row: 4, Col: 4, PrevNotes: game.NoteBit(2), NextNotes: game.NoteBit(2) | game.NoteBit(7), At: at}}
+	g.Elapsed = 90 * time.Second
+	g.HintsUsed = 2
+	g.Mistakes = 1
+	if err := Write(g); err != nil {
+		t.Fatal(err)
+	}
+	if !Exists() {
+		t.Fatal("Exists after Write = false")
+	}
+
+	got, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SavedAt.IsZero() {
+		t.Error("SavedAt not stamped")
+	}
+	got.SavedAt = time.Time{}
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("round trip changed the game:\n got %+v\nwant %+v", got, g)
+	}
+
+	b := got.Board()
+	if len(b.Regions) != 2 || !reflect.DeepEqual(b.Cages, g.Cages) || b.Notes != g.Notes || b.Values != g.Values {
+		t.Errorf("Board() = %+v", b)
+	}
+	if !got.Started() {
+		t.Error("Started() = false with a player digit on the board")
+	}
+	if got.Solved() {
+		t.Error("Solved() = true on a nearly empty board")
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if Exists() {
+		t.Error("Exists after Clear = true")
+	}
+	if err := Clear(); err != nil {
+		t.Errorf("second Clear: %v", err)
+	}
+}
+
+func TestStartedSolved(t *testing.T) {
+	var sol game.Grid
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			sol[r][c] = uint8((r*3+r/3+c)%9 + 1)
+		}
+	}
+	var g Game
+	g.Values = sol
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			g.Given[r][c] = true
+		}
+	}
+	if g.Started() {
+		t.Error("Started() = true with givens only")
+	}
+	if !g.Solved() {
+		t.Error("Solved() = false on a valid full grid")
+	}
+	g.Given[8][8] = false
+	if !g.Started() {
+		t.Error("Started() = false with a player digit")
+	}
+	// the pattern grid repeats digits on the main diagonal
+	g.Variant = "x-sudoku"
+	if g.Solved() {
+		t.Error("Solved() = true on an X-Sudoku with diagonal repeats")
+	}
+}
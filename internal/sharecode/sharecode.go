@@ -0,0 +1,269 @@
+// Package sharecode turns a puzzle into a short, copy-pasteable code and back.
+//
+// Generated puzzles are shared by recipe: difficulty letter, generator
+// version, optional symmetry and variant letters and the seed, e.g.
+// "H1-lq3z8f2kab", "H1R-friday-lunch" (rotational), "H1RX-friday-lunch"
+// (rotational X-Sudoku), "D1-2026-10-17" for a daily or "K1-lq3z8f2kab" for a
+// Killer Sudoku.
+// Since seeded generation is deterministic per generator.Version that is
+// enough to rebuild the exact puzzle. Anything else (imported puzzles) is
+// shared by its givens: "G-" plus the clue mask and digits packed in base 62.
+// Daily codes of days still ahead (UTC) are refused, as in the calendar.
+package sharecode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+)
+
+// Kind says how a code describes its puzzle.
+type Kind int
+
+const (
+	Seeded Kind = iota
+	Daily
+	Givens
+	// Killer codes carry the seed of a generator.GenerateKiller puzzle.
+	Killer
+)
+
+// Code is a decoded share code.
+type Code struct {
+	Kind Kind
+	// Difficulty and Seed describe Seeded codes; Daily codes carry their
+	// date in Seed (YYYY-MM-DD).
+	Difficulty generator.Difficulty
+	Symmetry   generator.Symmetry
+	Variant    game.Variant
+	Seed       string
+	// Givens holds the puzzle of Givens codes.
+	Givens generator.Grid
+}
+
+var (
+	// ErrInvalid is returned for text that isn't a share code.
+	ErrInvalid = errors.New("not a share code")
+	// ErrVersion is returned for seed codes of a different generator version,
+	// whose seed would now produce another puzzle.
+	ErrVersion = errors.New("share code is from a different generator version")
+	// ErrFuture is returned for daily codes of a day that hasn't started yet
+	// (in UTC), the same days the calendar won't open.
+	ErrFuture = errors.New("that daily puzzle isn't out yet")
+)
+
+// now is the clock future dailies are judged by, swapped out in tests.
+var now = time.Now
+
+var diffLetters = map[generator.Difficulty]byte{
+	generator.Easy:    'E',
+	generator.Normal:  'N',
+	generator.Hard:    'H',
+	generator.Lunatic: 'L',
+	generator.Minimal: 'X',
+}
+
+var symLetters = map[generator.Symmetry]string{
+	generator.NoSymmetry: "",
+	generator.Rotational: "R",
+	generator.Diagonal:   "D",
+	generator.Mirror:     "M",
+	generator.FourFold:   "F",
+}
+
+// variantLetters follow the symmetry letter; Classic has none.
+var variantLetters = map[game.Variant]string{
+	game.Classic: "",
+	game.XSudoku: "X",
+	game.Hyper:   "W",
+}
+
+// ForSeed is the code of a seeded puzzle of difficulty d carved with sym and
+// played as variant v.
+func ForSeed(d generator.Difficulty, sym generator.Symmetry, v game.Variant, seed string) string {
+	return fmt.Sprintf("%c%d%s%s-%s", diffLetters[d], generator.Version, symLetters[sym], variantLetters[v], seed)
+}
+
+// ForKiller is the code of the seeded Killer Sudoku.
+func ForKiller(seed string) string {
+	return fmt.Sprintf("K%d-%s", generator.Version, seed)
+}
+
+// ForDaily is the code of the daily puzzle of date.
+func ForDaily(date time.Time) string {
+	return fmt.Sprintf("D%d-%s", generator.Version, generator.DailySeed(date))
+}
+
+// ForGivens packs the clues of g: an 81-bit mask of given cells followed by
+// one base-9 digit per clue, written in base 62.
+func ForGivens(g generator.Grid) string {
+	n := new(big.Int)
+	nine := big.NewInt(9)
+	for i := 80; i >= 0; i-- {
+		if v := g[i/9][i%9]; v != 0 {
+			n.Mul(n, nine)
+			n.Add(n, big.NewInt(int64(v-1)))
+		}
+	}
+	n.Lsh(n, 81)
+	for i := 0; i < 81; i++ {
+		if g[i/9][i%9] != 0 {
+			n.SetBit(n, i, 1)
+		}
+	}
+	return "G-" + n.Text(62)
+}
+
+// Parse decodes a share code; surrounding whitespace and letter case of the
+// prefix are ignored.
+func Parse(s string) (Code, error) {
+	s = strings.TrimSpace(s)
+	prefix, body, ok := strings.Cut(s, "-")
+	if !ok || prefix == "" || body == "" {
+		return Code{}, ErrInvalid
+	}
+	prefix = strings.ToUpper(prefix)
+	if prefix == "G" {
+		return parseGivens(body)
+	}
+	digits := strings.TrimRight(prefix[1:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	version, err := strconv.Atoi(digits)
+	if err != nil {
+		return Code{}, ErrInvalid
+	}
+	sym, variant, ok := parseLetters(prefix[1+len(digits):])
+	if !ok {
+		return Code{}, ErrInvalid
+	}
+	var c Code
+	switch prefix[0] {
+	case 'D':
+		if _, err := time.Parse("2006-01-02", body); err != nil {
+			return Code{}, ErrInvalid
+		}
+		if sym != generator.NoSymmetry || variant != game.Classic {
+			return Code{}, ErrInvalid
+		}
+		// YYYY-MM-DD sorts like the dates it names
+		if body > generator.DailySeed(now()) {
+			return Code{}, fmt.Errorf("%w (%s)", ErrFuture, body)
+		}
+		c = Code{Kind: Daily, Seed: body}
+	case 'K':
+		if sym != generator.NoSymmetry || variant != game.Classic {
+			return Code{}, ErrInvalid
+		}
+		c = Code{Kind: Killer, Seed: body}
+	default:
+		found := false
+		for d, l := range diffLetters {
+			if l == prefix[0] {
+				c, found = Code{Kind: Seeded, Difficulty: d, Symmetry: sym, Variant: variant, Seed: body}, true
+			}
+		}
+		if !found {
+			return Code{}, ErrInvalid
+		}
+	}
+	if version != generator.Version {
+		return Code{}, fmt.Errorf("%w (v%d, this build makes v%d)", ErrVersion, version, generator.Version)
+	}
+	return c, nil
+}
+
+// parseLetters reads the optional symmetry letter, then the optional variant
+// letter, in that order and nothing else.
+func parseLetters(letters string) (generator.Symmetry, game.Variant, bool) {
+	sym, variant := generator.NoSymmetry, game.Classic
+	if letters != "" {
+		for s := generator.Rotational; s <= generator.FourFold; s++ {
+			if l := symLetters[s]; letters[:1] == l {
+				sym, letters = s, letters[1:]
+				break
+			}
+		}
+	}
+	if letters != "" {
+		for v := game.XSudoku; v <= game.Hyper; v++ {
+			if l := variantLetters[v]; letters == l {
+				variant, letters = v, ""
+				break
+			}
+		}
+	}
+	return sym, variant, letters == ""
+}
+
+func parseGivens(body string) (Code, error) {
+	n, ok := new(big.Int).SetString(body, 62)
+	if !ok || n.Sign() < 0 {
+		return Code{}, ErrInvalid
+	}
+	c := Code{Kind: Givens}
+	var mask [81]bool
+	for i := 0; i < 81; i++ {
+		mask[i] = n.Bit(i) == 1
+	}
+	n.Rsh(n, 81)
+	nine := big.NewInt(9)
+	digit := new(big.Int)
+	for i := 0; i < 81; i++ {
+		if !mask[i] { continue }
+		n.DivMod(n, nine, digit)
+		c.Givens[i/9][i%9] = uint8(digit.Int64()) + 1
+	}
+	if n.Sign() != 0 {
+		return Code{}, ErrInvalid
+	}
+	// a mistyped code usually still decodes, but rarely to legal givens
+	for _, row := range game.DuplicateMapAll(game.Grid(c.Givens)) {
+		for _, bad := range row {
+			if bad { return Code{}, ErrInvalid }
+		}
+	}
+	return c, nil
+}
+
+// String re-encodes c.
+func (c Code) String() string {
+	switch c.Kind {
+	case Daily:
+		return fmt.Sprintf("D%d-%s", generator.Version, c.Seed)
+	case Givens:
+		return ForGivens(c.Givens)
+	case Killer:
+		return ForKiller(c.Seed)
+	}
+	return ForSeed(c.Difficulty, c.Symmetry, c.Variant, c.Seed)
+}
+
+// Date is the day of a Daily code.
+func (c Code) Date() time.Time {
+	d, _ := time.Parse("2006-01-02", c.Seed)
+	return d
+}
+
+// Puzzle rebuilds the puzzle the code describes. Of a Killer code that is
+// only the givens; generator.GenerateKiller also returns the cages.
+func (c Code) Puzzle() (generator.Grid, error) {
+	switch c.Kind {
+	case Killer:
+		k, err := generator.GenerateKiller(context.Background(), c.Seed)
+		return k.Givens, err
+	case Daily:
+		return generator.GenerateDaily(c.Date())
+	case Givens:
+		return c.Givens, nil
+	}
+	p := generator.ParamsFor(c.Difficulty)
+	p.Symmetry = c.Symmetry
+	p.Variant = c.Variant
+	return generator.GenerateWithParams(p, c.Seed)
+}
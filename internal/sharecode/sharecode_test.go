@@ -0,0 +1,114 @@
+package sharecode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var codes []Code
+	for d := generator.Easy; d <= generator.Minimal; d++ {
+		for sym := generator.NoSymmetry; sym <= generator.FourFold; sym++ {
+			for v := game.Classic; v <= game.Hyper; v++ {
+				codes = append(codes, Code{Kind: Seeded, Difficulty: d, Symmetry: sym, Variant: v, Seed: "friday-lunch"})
+			}
+		}
+	}
+	g, err := game.ParseGrid("..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes = append(codes,
+		Code{Kind: Daily, Seed: "2026-10-17"},
+		Code{Kind: Killer, Seed: "lq3z8f2kab"},
+		Code{Kind: Givens, Givens: generator.Grid(g)},
+		Code{Kind: Givens},
+	)
+	for _, c := range codes {
+		s := c.String()
+		got, err := Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if got != c {
+			t.Errorf("Parse(%q) = %+v, want %+v", s, got, c)
+		}
+	}
+}
+
+func TestParseLetters(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Code
+	}{
+		{"X1-abc", Code{Kind: Seeded, Difficulty: generator.Minimal, Seed: "abc"}},
+		{"x1x-abc", Code{Kind: Seeded, Difficulty: generator.Minimal, Variant: game.XSudoku, Seed: "abc"}},
+		{"H1RX-friday-lunch", Code{Kind: Seeded, Difficulty: generator.Hard, Symmetry: generator.Rotational, Variant: game.XSudoku, Seed: "friday-lunch"}},
+		{"L1FW-a", Code{Kind: Seeded, Difficulty: generator.Lunatic, Symmetry: generator.FourFold, Variant: game.Hyper, Seed: "a"}},
+		{" E1D-a ", Code{Kind: Seeded, Difficulty: generator.Easy, Symmetry: generator.Diagonal, Seed: "a"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	p, err := game.ParseGrid("..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := ForGivens(generator.Grid(p))
+	for _, in := range []string{
+		"", "H1", "H1-", "-abc", "Q1-abc", "H-abc", "H1Q-abc", "H1XR-abc", "H1XX-abc", "H1RXW-abc", "H1WX-abc",
+		"D1R-2026-10-17", "D1X-2026-10-17", "D1-yesterday",
+		"K1X-abc", "K1R-abc",
+		"G-!!!", g + "zz", g[:len(g)-1], "G-1" + g[2:],
+	} {
+		if c, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) = %+v, %v; want ErrInvalid", in, c, err)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, in := range []string{
+		fmt.Sprintf("H%d-abc", generator.Version+1),
+		fmt.Sprintf("D%d-2026-10-17", generator.Version+1),
+		fmt.Sprintf("K%d-abc", generator.Version+1),
+		"H0RX-abc",
+	} {
+		if _, err := Parse(in); !errors.Is(err, ErrVersion) {
+			t.Errorf("Parse(%q): %v, want ErrVersion", in, err)
+		}
+	}
+}
+
+func TestParseFutureDaily(t *testing.T) {
+	defer func(old func() time.Time) { now = old }(now)
+	// 23:30 in New York is already the next day in UTC
+	now = func() time.Time { return time.Date(2026, 10, 17, 23, 30, 0, 0, time.FixedZone("EDT", -4*3600)) }
+	tests := []struct {
+		in      string
+		wantErr error
+	}{
+		{"D1-2026-10-17", nil},
+		{"D1-2026-10-18", nil},
+		{"D1-2026-10-19", ErrFuture},
+		{"D1-2027-01-01", ErrFuture},
+		{"D1-2025-12-31", nil},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.in); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q): %v, want %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package solver
+
+import (
+	"context"
+	"math/bits"
+)
+
+// Cage is a Killer Sudoku cage over cell indexes (row*9+col): the cells hold
+// distinct digits adding up to Sum.
+type Cage struct {
+	Sum   int
+	Cells []int
+}
+
+// CheckKillerUniquenessNodes is CheckUniquenessNodes with cages: g holds the
+// givens (often none) and every cage sum and no-repeat rule is enforced.
+func CheckKillerUniquenessNodes(g Grid, cages []Cage, maxNodes int) Uniqueness {
+	sols, expired := killerSolutions(context.Background(), g, cages, 2, maxNodes)
+	switch {
+	case expired:
+		return Unknown
+	case len(sols) == 0:
+		return NoSolution
+	case len(sols) == 1:
+		return Unique
+	}
+	return Multiple
+}
+
+// KillerSolutions returns up to max solutions of g under cages within
+// maxNodes search nodes; expired reports that the budget ran out first.
+func KillerSolutions(g Grid, cages []Cage, max, maxNodes int) (sols []Grid, expired bool) {
+	return killerSolutions(context.Background(), g, cages, max, maxNodes)
+}
+
+func killerSolutions(ctx context.Context, g Grid, cages []Cage, max, maxNodes int) ([]Grid, bool) {
+	s, ok := newKillerSearch(ctx, g, cages)
+	if !ok {
+		return nil, false
+	}
+	s.limit = maxNodes
+	var out []Grid
+	s.run(func() bool {
+		out = append(out, s.grid())
+		return len(out) >= max
+	})
+	return out, s.expired
+}
+
+// cageState tracks a cage during the search: the digits placed so far, their
+// sum, how many cells are still empty and, cached, the digits those can take.
+type cageState struct {
+	target int
+	sum    int
+	used   uint16
+	left   int
+	mask   uint16
+}
+
+func (k *cageState) place(v uint8) {
+	k.used |= 1 << (v - 1)
+	k.sum += int(v)
+	k.left--
+	k.mask = k.allowed()
+}
+
+func (k *cageState) remove(v uint8) {
+	k.used &^= 1 << (v - 1)
+	k.sum -= int(v)
+	k.left++
+	k.mask = k.allowed()
+}
+
+// allowed returns the digits an empty cell of the cage can still take: unused
+// ones after which the other empty cells can reach the sum with distinct
+// unused digits (checked against the smallest and largest such totals).
+func (k *cageState) allowed() uint16 {
+	if k.left == 0 {
+		return 0
+	}
+	free := ^k.used & 0x1ff
+	rest := k.target - k.sum
+	var m uint16
+	for f := free; f != 0; f &= f - 1 {
+		d := bits.TrailingZeros16(f) + 1
+		r := rest - d
+		if k.left == 1 {
+			if r == 0 { m |= 1 << (d - 1) }
+			continue
+		}
+		lo, hi := sumRange(free&^(1<<(d-1)), k.left-1)
+		if lo <= r && r <= hi {
+			m |= 1 << (d - 1)
+		}
+	}
+	return m
+}
+
+// sumRange returns the smallest and largest totals of n distinct digits of mask.
+func sumRange(mask uint16, n int) (lo, hi int) {
+	if bits.OnesCount16(mask) < n {
+		return 1, 0
+	}
+	for i, f := 0, mask; i < n; i, f = i+1, f&(f-1) {
+		lo += bits.TrailingZeros16(f) + 1
+	}
+	for i, f := 0, mask; i < n; i++ {
+		d := 16 - bits.LeadingZeros16(f)
+		hi += d
+		f &^= 1 << (d - 1)
+	}
+	return lo, hi
+}
+
+// newKillerSearch loads g under cages; it reports false when the givens clash
+// or a cage can no longer reach its sum.
+func newKillerSearch(ctx context.Context, g Grid, cages []Cage) (*search, bool) {
+	s := &search{ctx: ctx, cages: make([]cageState, len(cages))}
+	for i := range s.cageOf {
+		s.cageOf[i] = -1
+	}
+	for i, k := range cages {
+		s.cages[i] = cageState{target: k.Sum, left: len(k.Cells)}
+		s.cages[i].mask = s.cages[i].allowed()
+		for _, idx := range k.Cells {
+			if idx < 0 || idx >= 81 || s.cageOf[idx] >= 0 {
+				return nil, false
+			}
+			s.cageOf[idx] = int8(i)
+		}
+	}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			v := g[r][c]
+			if v == 0 {
+				s.empty = append(s.empty, r*9+c)
+				continue
+			}
+			if v > 9 || s.free(r*9+c)&(1<<(v-1)) == 0 {
+				return nil, false
+			}
+			s.set(r*9+c, v)
+		}
+	}
+	for _, k := range s.cages {
+		if (k.left == 0 && k.sum != k.target) || (k.left > 0 && k.mask == 0) {
+			return nil, false
+		}
+	}
+	return s, true
+}
@@ -0,0 +1,38 @@
+package solver
+
+import "testing"
+
+const killerSolved = "123456789456789123789123456214365897365897214897214365531642978642978531978531642"
+
+// A cage per cell fixes every digit; one cage per row says nothing a row
+// doesn't already, so an empty grid stays open.
+func TestKillerUniqueness(t *testing.T) {
+	sol := mustGrid(t, killerSolved)
+	var single, rows []Cage
+	for idx := 0; idx < 81; idx++ {
+		single = append(single, Cage{Sum: int(sol[idx/9][idx%9]), Cells: []int{idx}})
+	}
+	for r := 0; r < 9; r++ {
+		k := Cage{Sum: 45}
+		for c := 0; c < 9; c++ {
+			k.Cells = append(k.Cells, r*9+c)
+		}
+		rows = append(rows, k)
+	}
+	var empty Grid
+	if u := CheckKillerUniquenessNodes(empty, single, 1<<16); u != Unique {
+		t.Errorf("single-cell cages: %v, want Unique", u)
+	}
+	sols, expired := KillerSolutions(empty, single, 2, 1<<16)
+	if expired || len(sols) != 1 || sols[0] != sol {
+		t.Errorf("single-cell cages solved to %v (expired %v)", sols, expired)
+	}
+	if u := CheckKillerUniquenessNodes(empty, rows, 1<<16); u != Multiple {
+		t.Errorf("row cages: %v, want Multiple", u)
+	}
+	// a wrong sum leaves no solution
+	single[0].Sum = 2
+	if u := CheckKillerUniquenessNodes(empty, single, 1<<16); u != NoSolution {
+		t.Errorf("contradicting cage: %v, want NoSolution", u)
+	}
+}
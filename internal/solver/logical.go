@@ -0,0 +1,1006 @@
+package solver
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Technique is a named human solving technique. Values are ordered from the
+// easiest to the hardest, which is also the order the logical solver tries them.
+type Technique int
+
+const (
+	NakedSingle Technique = iota
+	HiddenSingle
+	PointingPair
+	BoxLineReduction
+	NakedPair
+	HiddenPair
+	NakedTriple
+	HiddenTriple
+	NakedQuad
+	HiddenQuad
+	XWing
+	Swordfish
+	XYWing
+	SimpleColoring
+	// Backtracking is not a technique the solver applies; it grades puzzles
+	// that the techniques above cannot finish.
+	Backtracking
+)
+
+var techniqueNames = [...]string{
+	NakedSingle:      "Naked single",
+	HiddenSingle:     "Hidden single",
+	PointingPair:     "Pointing pair",
+	BoxLineReduction: "Box/line reduction",
+	NakedPair:        "Naked pair",
+	HiddenPair:       "Hidden pair",
+	NakedTriple:      "Naked triple",
+	HiddenTriple:     "Hidden triple",
+	NakedQuad:        "Naked quad",
+	HiddenQuad:       "Hidden quad",
+	XWing:            "X-Wing",
+	Swordfish:        "Swordfish",
+	XYWing:           "XY-Wing",
+	SimpleColoring:   "Simple coloring",
+	Backtracking:     "Trial and error",
+}
+
+// techniqueWeights feed the numeric difficulty score: each applied step adds
+// the weight of its technique.
+var techniqueWeights = [...]int{
+	NakedSingle:      1,
+	HiddenSingle:     2,
+	PointingPair:     6,
+	BoxLineReduction: 6,
+	NakedPair:        8,
+	HiddenPair:       10,
+	NakedTriple:      12,
+	HiddenTriple:     14,
+	NakedQuad:        18,
+	HiddenQuad:       20,
+	XWing:            25,
+	Swordfish:        35,
+	XYWing:           35,
+	SimpleColoring:   40,
+	Backtracking:     200,
+}
+
+func (t Technique) String() string {
+	if t < 0 || int(t) >= len(techniqueNames) {
+		return fmt.Sprintf("Technique(%d)", int(t))
+	}
+	return techniqueNames[t]
+}
+
+// HouseKind distinguishes rows, columns, 3x3 boxes and the extra regions of
+// a variant.
+type HouseKind int
+
+const (
+	RowHouse HouseKind = iota
+	ColumnHouse
+	BoxHouse
+	RegionHouse
+)
+
+// House is one of the 27 units of nine cells, or an extra Region. Index is
+// 0-based; Name is set for regions.
+type House struct {
+	Kind  HouseKind
+	Index int
+	Name  string
+}
+
+func (h House) String() string {
+	switch h.Kind {
+	case RowHouse:
+		return fmt.Sprintf("row %d", h.Index+1)
+	case ColumnHouse:
+		return fmt.Sprintf("column %d", h.Index+1)
+	case RegionHouse:
+		return h.Name
+	default:
+		return fmt.Sprintf("box %d", h.Index+1)
+	}
+}
+
+// Region is an extra house of a variant (an X-Sudoku diagonal, a Hyper
+// window...): nine cell indexes (row*9+col) holding every digit once. Name
+// shows up in hint texts.
+type Region struct {
+	Name  string
+	Cells [9]int
+}
+
+// Cell addresses a grid cell with 0-based row and column.
+type Cell struct {
+	Row int
+	Col int
+}
+
+func (c Cell) String() string { return fmt.Sprintf("r%dc%d", c.Row+1, c.Col+1) }
+
+// Candidate is a digit in a specific cell, used for placements and eliminations.
+type Candidate struct {
+	Row   int
+	Col   int
+	Digit uint8
+}
+
+func (c Candidate) Cell() Cell { return Cell{Row: c.Row, Col: c.Col} }
+
+// Step is a single logical deduction.
+type Step struct {
+	Technique Technique
+	// Digits the pattern is about (e.g. the pair in a naked pair).
+	Digits []uint8
+	// Cells forming the pattern.
+	Cells []Cell
+	// Houses the pattern lives in (base rows of a fish, the house of a single...).
+	Houses []House
+	// Placements are digits this step puts into the grid (singles only).
+	Placements []Candidate
+	// Eliminations are candidates this step removes.
+	Eliminations []Candidate
+}
+
+// Description explains the step in one line, e.g.
+// "Hidden single: 7 in row 4 must go in r4c6".
+func (s Step) Description() string {
+	name := s.Technique.String()
+	switch s.Technique {
+	case NakedSingle:
+		p := s.Placements[0]
+		return fmt.Sprintf("%s: %s can only be %d", name, p.Cell(), p.Digit)
+	case HiddenSingle:
+		p := s.Placements[0]
+		return fmt.Sprintf("%s: %d in %s must go in %s", name, p.Digit, s.Houses[0], p.Cell())
+	case PointingPair, BoxLineReduction:
+		return fmt.Sprintf("%s: %d in %s is confined to %s; remove %s",
+			name, s.Digits[0], s.Houses[0], s.Houses[1], elimText(s.Eliminations))
+	case NakedPair, NakedTriple, NakedQuad:
+		return fmt.Sprintf("%s %s in %s (%s); remove %s",
+			name, digitSet(s.Digits), s.Houses[0], cellList(s.Cells), elimText(s.Eliminations))
+	case HiddenPair, HiddenTriple, HiddenQuad:
+		return fmt.Sprintf("%s %s in %s (%s); remove %s",
+			name, digitSet(s.Digits), s.Houses[0], cellList(s.Cells), elimText(s.Eliminations))
+	case XWing, Swordfish:
+		var base []string
+		for _, h := range s.Houses {
+			base = append(base, h.String())
+		}
+		return fmt.Sprintf("%s on %d in %s; remove %s", name, s.Digits[0], strings.Join(base, ", "), elimText(s.Eliminations))
+	case XYWing:
+		return fmt.Sprintf("%s with pivot %s and pincers %s, %s; remove %s",
+			name, s.Cells[0], s.Cells[1], s.Cells[2], elimText(s.Eliminations))
+	case SimpleColoring:
+		return fmt.Sprintf("%s on %d (%s); remove %s", name, s.Digits[0], cellList(s.Cells), elimText(s.Eliminations))
+	}
+	return name
+}
+
+func (s Step) String() string { return s.Description() }
+
+func digitSet(ds []uint8) string {
+	parts := make([]string, len(ds))
+	for i, d := range ds {
+		parts[i] = fmt.Sprint(d)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func cellList(cs []Cell) string {
+	parts := make([]string, len(cs))
+	for i, c := range cs {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// elimText groups eliminations by digit: "3 from r1c2, r1c5; 7 from r1c5".
+func elimText(es []Candidate) string {
+	var order []uint8
+	byDigit := map[uint8][]string{}
+	for _, e := range es {
+		if _, ok := byDigit[e.Digit]; !ok {
+			order = append(order, e.Digit)
+		}
+		byDigit[e.Digit] = append(byDigit[e.Digit], e.Cell().String())
+	}
+	parts := make([]string, len(order))
+	for i, d := range order {
+		parts[i] = fmt.Sprintf("%d from %s", d, strings.Join(byDigit[d], ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// LogicalResult is the outcome of SolveLogical.
+type LogicalResult struct {
+	// Steps in the order they were applied.
+	Steps []Step
+	// Grid after applying every step.
+	Grid Grid
+	// Solved is true when the techniques alone filled the grid.
+	Solved bool
+	// Invalid is true when the input breaks the rules (duplicate digits or a
+	// cell without candidates), in which case no steps are attempted.
+	Invalid bool
+}
+
+// Hardest returns the most advanced technique used, or false when no steps were taken.
+func (r LogicalResult) Hardest() (Technique, bool) {
+	if len(r.Steps) == 0 {
+		return 0, false
+	}
+	max := r.Steps[0].Technique
+	for _, s := range r.Steps[1:] {
+		if s.Technique > max {
+			max = s.Technique
+		}
+	}
+	return max, true
+}
+
+// Grade describes how hard a puzzle is for a human solver.
+type Grade struct {
+	// Hardest technique needed; Backtracking when the techniques get stuck.
+	Hardest Technique
+	// Score sums the technique weights of every step, plus a Backtracking
+	// penalty when the logical solver cannot finish.
+	Score int
+	// Steps is the number of logical steps applied.
+	Steps int
+}
+
+// GradePuzzle runs the logical solver on g and grades the result.
+func GradePuzzle(g Grid) Grade { return GradePuzzleWith(g, nil) }
+
+// GradePuzzleWith is GradePuzzle with extra regions.
+func GradePuzzleWith(g Grid, regions []Region) Grade {
+	res := SolveLogicalWith(g, regions)
+	var gr Grade
+	gr.Steps = len(res.Steps)
+	for _, s := range res.Steps {
+		gr.Score += techniqueWeights[s.Technique]
+		if s.Technique > gr.Hardest {
+			gr.Hardest = s.Technique
+		}
+	}
+	if !res.Solved {
+		gr.Hardest = Backtracking
+		gr.Score += techniqueWeights[Backtracking]
+	}
+	return gr
+}
+
+// SolveLogical solves g using only the named techniques, always applying the
+// easiest technique that makes progress.
+func SolveLogical(g Grid) LogicalResult { return SolveLogicalWith(g, nil) }
+
+// SolveLogicalWith is SolveLogical with extra regions: they take part in
+// candidates, singles, subsets, wings and coloring like any other house.
+func SolveLogicalWith(g Grid, regions []Region) LogicalResult {
+	st, ok := newLogicState(g, newHouseSet(regions))
+	if !ok {
+		return LogicalResult{Grid: g, Invalid: true}
+	}
+	var res LogicalResult
+	for !st.solved() {
+		if st.broken() {
+			res.Invalid = true
+			break
+		}
+		step, ok := st.next()
+		if !ok {
+			break
+		}
+		st.apply(step)
+		res.Steps = append(res.Steps, step)
+	}
+	res.Grid = st.grid()
+	res.Solved = st.solved()
+	return res
+}
+
+// NextStep returns the easiest deduction available from the filled digits of g,
+// with candidates derived from those digits alone.
+func NextStep(g Grid) (Step, bool) { return NextStepWith(g, nil) }
+
+// NextStepWith is NextStep with extra regions.
+func NextStepWith(g Grid, regions []Region) (Step, bool) {
+	st, ok := newLogicState(g, newHouseSet(regions))
+	if !ok || st.broken() {
+		return Step{}, false
+	}
+	return st.next()
+}
+
+// ---- internal state --------------------------------------------------------
+
+const allDigits uint16 = 0x1ff
+
+// cellHouses holds the row, column and box house of every cell.
+var cellHouses [81][3]int
+
+// houseSet is the list of houses a grid is solved with: rows 0-8, columns
+// 9-17 and boxes 18-26, then the extra regions, with the peers they imply.
+type houseSet struct {
+	list  []House
+	cells [][9]int
+	peers [81][]int
+	seen  [81][81]bool
+	// regionsOf lists the extra regions (0-based) of each cell.
+	regionsOf [81][]int
+}
+
+var classicHouses *houseSet
+
+func init() {
+	for idx := 0; idx < 81; idx++ {
+		r, c := idx/9, idx%9
+		cellHouses[idx] = [3]int{r, 9 + c, 18 + (r/3)*3 + c/3}
+	}
+	classicHouses = buildHouseSet(nil)
+}
+
+// newHouseSet returns the houses for regions; classic grids share one set.
+func newHouseSet(regions []Region) *houseSet {
+	if len(regions) == 0 {
+		return classicHouses
+	}
+	return buildHouseSet(regions)
+}
+
+func buildHouseSet(regions []Region) *houseSet {
+	hs := &houseSet{}
+	for _, kind := range []HouseKind{RowHouse, ColumnHouse, BoxHouse} {
+		for i := 0; i < 9; i++ {
+			var cells [9]int
+			for j := 0; j < 9; j++ {
+				switch kind {
+				case RowHouse:
+					cells[j] = i*9 + j
+				case ColumnHouse:
+					cells[j] = j*9 + i
+				default:
+					cells[j] = ((i/3)*3+j/3)*9 + (i%3)*3 + j%3
+				}
+			}
+			hs.add(House{Kind: kind, Index: i}, cells)
+		}
+	}
+	for i, r := range regions {
+		hs.add(House{Kind: RegionHouse, Index: i, Name: r.Name}, r.Cells)
+		for _, idx := range r.Cells {
+			hs.regionsOf[idx] = append(hs.regionsOf[idx], i)
+		}
+	}
+	for _, cells := range hs.cells {
+		for _, a := range cells {
+			for _, b := range cells {
+				if a != b { hs.seen[a][b] = true }
+			}
+		}
+	}
+	for a := 0; a < 81; a++ {
+		for b := 0; b < 81; b++ {
+			if hs.seen[a][b] {
+				hs.peers[a] = append(hs.peers[a], b)
+			}
+		}
+	}
+	return hs
+}
+
+func (hs *houseSet) add(h House, cells [9]int) {
+	hs.list = append(hs.list, h)
+	hs.cells = append(hs.cells, cells)
+}
+
+func (hs *houseSet) sees(a, b int) bool { return hs.seen[a][b] }
+
+func bit(v uint8) uint16 { return 1 << (v - 1) }
+
+func maskDigits(m uint16) []uint8 {
+	var out []uint8
+	for v := uint8(1); v <= 9; v++ {
+		if m&bit(v) != 0 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func cellOf(idx int) Cell { return Cell{Row: idx / 9, Col: idx % 9} }
+
+type logicState struct {
+	vals [81]uint8
+	cand [81]uint16
+	hs   *houseSet
+}
+
+func newLogicState(g Grid, hs *houseSet) (*logicState, bool) {
+	st := &logicState{hs: hs}
+	for i := range st.cand {
+		st.cand[i] = allDigits
+	}
+	for idx := 0; idx < 81; idx++ {
+		v := g[idx/9][idx%9]
+		if v == 0 {
+			continue
+		}
+		if v > 9 || st.cand[idx]&bit(v) == 0 {
+			return nil, false
+		}
+		st.place(idx, v)
+	}
+	return st, true
+}
+
+func (st *logicState) place(idx int, v uint8) {
+	st.vals[idx] = v
+	st.cand[idx] = 0
+	for _, p := range st.hs.peers[idx] {
+		st.cand[p] &^= bit(v)
+	}
+}
+
+func (st *logicState) apply(s Step) {
+	for _, p := range s.Placements {
+		st.place(p.Row*9+p.Col, p.Digit)
+	}
+	for _, e := range s.Eliminations {
+		st.cand[e.Row*9+e.Col] &^= bit(e.Digit)
+	}
+}
+
+func (st *logicState) solved() bool {
+	for _, v := range st.vals {
+		if v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// broken reports an empty cell with no candidates left.
+func (st *logicState) broken() bool {
+	for i, v := range st.vals {
+		if v == 0 && st.cand[i] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *logicState) grid() Grid {
+	var g Grid
+	for i, v := range st.vals {
+		g[i/9][i%9] = v
+	}
+	return g
+}
+
+type finder func(*logicState) (Step, bool)
+
+// finders are tried in order; the first one that makes progress wins.
+var finders = []finder{
+	findNakedSingle,
+	findHiddenSingle,
+	findPointing,
+	findBoxLine,
+	nakedSubset(2, NakedPair),
+	hiddenSubset(2, HiddenPair),
+	nakedSubset(3, NakedTriple),
+	hiddenSubset(3, HiddenTriple),
+	nakedSubset(4, NakedQuad),
+	hiddenSubset(4, HiddenQuad),
+	fish(2, XWing),
+	fish(3, Swordfish),
+	findXYWing,
+	findSimpleColoring,
+}
+
+func (st *logicState) next() (Step, bool) {
+	for _, f := range finders {
+		if s, ok := f(st); ok {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// digitCells returns the empty cells of house h that still allow digit v.
+func (st *logicState) digitCells(h int, v uint8) []int {
+	var out []int
+	for _, idx := range st.hs.cells[h] {
+		if st.cand[idx]&bit(v) != 0 {
+			out = append(out, idx)
+		}
+	}
+	return out
+}
+
+func findNakedSingle(st *logicState) (Step, bool) {
+	for idx := 0; idx < 81; idx++ {
+		if st.vals[idx] == 0 && bits.OnesCount16(st.cand[idx]) == 1 {
+			v := uint8(bits.TrailingZeros16(st.cand[idx]) + 1)
+			return Step{
+				Technique:  NakedSingle,
+				Digits:     []uint8{v},
+				Cells:      []Cell{cellOf(idx)},
+				Placements: []Candidate{{Row: idx / 9, Col: idx % 9, Digit: v}},
+			}, true
+		}
+	}
+	return Step{}, false
+}
+
+func findHiddenSingle(st *logicState) (Step, bool) {
+	// boxes first: that is where players usually spot them; extra regions last
+	order := []int{18, 19, 20, 21, 22, 23, 24, 25, 26, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17}
+	for h := 27; h < len(st.hs.cells); h++ {
+		order = append(order, h)
+	}
+	for _, h := range order {
+		for v := uint8(1); v <= 9; v++ {
+			cells := st.digitCells(h, v)
+			if len(cells) != 1 {
+				continue
+			}
+			idx := cells[0]
+			return Step{
+				Technique:  HiddenSingle,
+				Digits:     []uint8{v},
+				Cells:      []Cell{cellOf(idx)},
+				Houses:     []House{st.hs.list[h]},
+				Placements: []Candidate{{Row: idx / 9, Col: idx % 9, Digit: v}},
+			}, true
+		}
+	}
+	return Step{}, false
+}
+
+// lockedCandidates removes v from every cell of house target outside of base.
+func (st *logicState) lockedElims(target int, base []int, v uint8) []Candidate {
+	var elims []Candidate
+	for _, idx := range st.hs.cells[target] {
+		if st.cand[idx]&bit(v) == 0 || containsInt(base, idx) {
+			continue
+		}
+		elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+	}
+	return elims
+}
+
+func findPointing(st *logicState) (Step, bool) {
+	for b := 18; b < 27; b++ {
+		for v := uint8(1); v <= 9; v++ {
+			cells := st.digitCells(b, v)
+			if len(cells) < 2 {
+				continue
+			}
+			for _, line := range sharedLines(cells) {
+				if elims := st.lockedElims(line, cells, v); len(elims) > 0 {
+					return Step{
+						Technique:    PointingPair,
+						Digits:       []uint8{v},
+						Cells:        cellsOf(cells),
+						Houses:       []House{st.hs.list[b], st.hs.list[line]},
+						Eliminations: elims,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+func findBoxLine(st *logicState) (Step, bool) {
+	for line := 0; line < 18; line++ {
+		for v := uint8(1); v <= 9; v++ {
+			cells := st.digitCells(line, v)
+			if len(cells) < 2 {
+				continue
+			}
+			box := cellHouses[cells[0]][2]
+			same := true
+			for _, idx := range cells[1:] {
+				if cellHouses[idx][2] != box {
+					same = false
+					break
+				}
+			}
+			if !same {
+				continue
+			}
+			if elims := st.lockedElims(box, cells, v); len(elims) > 0 {
+				return Step{
+					Technique:    BoxLineReduction,
+					Digits:       []uint8{v},
+					Cells:        cellsOf(cells),
+					Houses:       []House{st.hs.list[line], st.hs.list[box]},
+					Eliminations: elims,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// sharedLines returns the row and/or column houses containing every cell.
+func sharedLines(cells []int) []int {
+	var out []int
+	row, col := cellHouses[cells[0]][0], cellHouses[cells[0]][1]
+	sameRow, sameCol := true, true
+	for _, idx := range cells[1:] {
+		if cellHouses[idx][0] != row {
+			sameRow = false
+		}
+		if cellHouses[idx][1] != col {
+			sameCol = false
+		}
+	}
+	if sameRow {
+		out = append(out, row)
+	}
+	if sameCol {
+		out = append(out, col)
+	}
+	return out
+}
+
+func nakedSubset(n int, t Technique) finder {
+	return func(st *logicState) (Step, bool) {
+		for h := 0; h < len(st.hs.cells); h++ {
+			var pool []int
+			for _, idx := range st.hs.cells[h] {
+				if c := bits.OnesCount16(st.cand[idx]); st.vals[idx] == 0 && c >= 2 && c <= n {
+					pool = append(pool, idx)
+				}
+			}
+			var found Step
+			ok := combinations(len(pool), n, func(pick []int) bool {
+				var union uint16
+				chosen := make([]int, n)
+				for i, p := range pick {
+					chosen[i] = pool[p]
+					union |= st.cand[pool[p]]
+				}
+				if bits.OnesCount16(union) != n {
+					return false
+				}
+				var elims []Candidate
+				for _, idx := range st.hs.cells[h] {
+					if containsInt(chosen, idx) {
+						continue
+					}
+					for _, v := range maskDigits(st.cand[idx] & union) {
+						elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+					}
+				}
+				if len(elims) == 0 {
+					return false
+				}
+				found = Step{
+					Technique:    t,
+					Digits:       maskDigits(union),
+					Cells:        cellsOf(chosen),
+					Houses:       []House{st.hs.list[h]},
+					Eliminations: elims,
+				}
+				return true
+			})
+			if ok {
+				return found, true
+			}
+		}
+		return Step{}, false
+	}
+}
+
+func hiddenSubset(n int, t Technique) finder {
+	return func(st *logicState) (Step, bool) {
+		for h := 0; h < len(st.hs.cells); h++ {
+			var digits []uint8
+			var where [10]uint16 // digit -> bitset of positions within the house
+			for v := uint8(1); v <= 9; v++ {
+				for pos, idx := range st.hs.cells[h] {
+					if st.cand[idx]&bit(v) != 0 {
+						where[v] |= 1 << pos
+					}
+				}
+				if c := bits.OnesCount16(where[v]); c >= 2 && c <= n {
+					digits = append(digits, v)
+				}
+			}
+			var found Step
+			ok := combinations(len(digits), n, func(pick []int) bool {
+				var posUnion, keep uint16
+				chosen := make([]uint8, n)
+				for i, p := range pick {
+					chosen[i] = digits[p]
+					posUnion |= where[digits[p]]
+					keep |= bit(digits[p])
+				}
+				if bits.OnesCount16(posUnion) != n {
+					return false
+				}
+				var cells []int
+				var elims []Candidate
+				for pos, idx := range st.hs.cells[h] {
+					if posUnion&(1<<pos) == 0 {
+						continue
+					}
+					cells = append(cells, idx)
+					for _, v := range maskDigits(st.cand[idx] &^ keep) {
+						elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+					}
+				}
+				if len(elims) == 0 {
+					return false
+				}
+				found = Step{
+					Technique:    t,
+					Digits:       chosen,
+					Cells:        cellsOf(cells),
+					Houses:       []House{st.hs.list[h]},
+					Eliminations: elims,
+				}
+				return true
+			})
+			if ok {
+				return found, true
+			}
+		}
+		return Step{}, false
+	}
+}
+
+// fish finds X-Wing (n=2) and Swordfish (n=3) patterns on rows, then columns.
+func fish(n int, t Technique) finder {
+	return func(st *logicState) (Step, bool) {
+		for v := uint8(1); v <= 9; v++ {
+			for _, rowsBase := range []bool{true, false} {
+				baseOff, coverOff := 0, 9
+				if !rowsBase {
+					baseOff, coverOff = 9, 0
+				}
+				var lines []int
+				var spans [9]uint16
+				for i := 0; i < 9; i++ {
+					for pos, idx := range st.hs.cells[baseOff+i] {
+						if st.cand[idx]&bit(v) != 0 {
+							spans[i] |= 1 << pos
+						}
+					}
+					if c := bits.OnesCount16(spans[i]); c >= 2 && c <= n {
+						lines = append(lines, i)
+					}
+				}
+				var found Step
+				ok := combinations(len(lines), n, func(pick []int) bool {
+					var cover uint16
+					var base []int
+					for _, p := range pick {
+						cover |= spans[lines[p]]
+						base = append(base, lines[p])
+					}
+					if bits.OnesCount16(cover) != n {
+						return false
+					}
+					var elims []Candidate
+					var cells []int
+					for pos := 0; pos < 9; pos++ {
+						if cover&(1<<pos) == 0 {
+							continue
+						}
+						for i, idx := range st.hs.cells[coverOff+pos] {
+							if st.cand[idx]&bit(v) == 0 {
+								continue
+							}
+							if containsInt(base, i) {
+								cells = append(cells, idx)
+							} else {
+								elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+							}
+						}
+					}
+					if len(elims) == 0 {
+						return false
+					}
+					hs := make([]House, len(base))
+					for i, b := range base {
+						hs[i] = st.hs.list[baseOff+b]
+					}
+					found = Step{
+						Technique:    t,
+						Digits:       []uint8{v},
+						Cells:        cellsOf(cells),
+						Houses:       hs,
+						Eliminations: elims,
+					}
+					return true
+				})
+				if ok {
+					return found, true
+				}
+			}
+		}
+		return Step{}, false
+	}
+}
+
+func findXYWing(st *logicState) (Step, bool) {
+	for pivot := 0; pivot < 81; pivot++ {
+		pm := st.cand[pivot]
+		if st.vals[pivot] != 0 || bits.OnesCount16(pm) != 2 {
+			continue
+		}
+		var wings []int
+		for _, p := range st.hs.peers[pivot] {
+			wm := st.cand[p]
+			if st.vals[p] == 0 && bits.OnesCount16(wm) == 2 && bits.OnesCount16(wm&pm) == 1 {
+				wings = append(wings, p)
+			}
+		}
+		for i := 0; i < len(wings); i++ {
+			for j := i + 1; j < len(wings); j++ {
+				a, b := st.cand[wings[i]], st.cand[wings[j]]
+				// pincers share z, which the pivot lacks, and cover both pivot digits
+				z := a & b &^ pm
+				if bits.OnesCount16(z) != 1 || (a|b)&pm != pm || a == b {
+					continue
+				}
+				zd := maskDigits(z)[0]
+				var elims []Candidate
+				for idx := 0; idx < 81; idx++ {
+					if idx == pivot || st.cand[idx]&z == 0 {
+						continue
+					}
+					if st.hs.sees(idx, wings[i]) && st.hs.sees(idx, wings[j]) {
+						elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: zd})
+					}
+				}
+				if len(elims) > 0 {
+					return Step{
+						Technique:    XYWing,
+						Digits:       maskDigits(pm | z),
+						Cells:        cellsOf([]int{pivot, wings[i], wings[j]}),
+						Eliminations: elims,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// findSimpleColoring colors conjugate-pair chains of one digit in two colors.
+// Two cells of the same color seeing each other make that color false (color
+// wrap); a cell seeing both colors can never hold the digit (color trap).
+func findSimpleColoring(st *logicState) (Step, bool) {
+	for v := uint8(1); v <= 9; v++ {
+		var links [81][]int
+		for h := 0; h < len(st.hs.cells); h++ {
+			cells := st.digitCells(h, v)
+			if len(cells) == 2 {
+				links[cells[0]] = append(links[cells[0]], cells[1])
+				links[cells[1]] = append(links[cells[1]], cells[0])
+			}
+		}
+		color := [81]int{}
+		for start := 0; start < 81; start++ {
+			if len(links[start]) == 0 || color[start] != 0 {
+				continue
+			}
+			// breadth-first 2-coloring of this chain: colors 1 and 2
+			var chain []int
+			color[start] = 1
+			queue := []int{start}
+			for len(queue) > 0 {
+				cur := queue[0]
+				queue = queue[1:]
+				chain = append(chain, cur)
+				for _, nb := range links[cur] {
+					if color[nb] == 0 {
+						color[nb] = 3 - color[cur]
+						queue = append(queue, nb)
+					}
+				}
+			}
+			if len(chain) < 3 {
+				continue
+			}
+			// color wrap
+			for _, c := range []int{1, 2} {
+				wrap := false
+				for i := 0; i < len(chain) && !wrap; i++ {
+					for j := i + 1; j < len(chain); j++ {
+						if color[chain[i]] == c && color[chain[j]] == c && st.hs.sees(chain[i], chain[j]) {
+							wrap = true
+							break
+						}
+					}
+				}
+				if !wrap {
+					continue
+				}
+				var elims []Candidate
+				for _, idx := range chain {
+					if color[idx] == c {
+						elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+					}
+				}
+				return Step{Technique: SimpleColoring, Digits: []uint8{v}, Cells: cellsOf(chain), Eliminations: elims}, true
+			}
+			// color trap
+			var elims []Candidate
+			for idx := 0; idx < 81; idx++ {
+				if st.cand[idx]&bit(v) == 0 || containsInt(chain, idx) {
+					continue
+				}
+				seen := 0
+				for _, ci := range chain {
+					if st.hs.sees(idx, ci) {
+						seen |= color[ci]
+					}
+				}
+				if seen == 3 {
+					elims = append(elims, Candidate{Row: idx / 9, Col: idx % 9, Digit: v})
+				}
+			}
+			if len(elims) > 0 {
+				return Step{Technique: SimpleColoring, Digits: []uint8{v}, Cells: cellsOf(chain), Eliminations: elims}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// combinations calls fn with every k-subset of 0..n-1 until fn returns true.
+func combinations(n, k int, fn func([]int) bool) bool {
+	if k > n || k <= 0 {
+		return false
+	}
+	pick := make([]int, k)
+	for i := range pick {
+		pick[i] = i
+	}
+	for {
+		if fn(pick) {
+			return true
+		}
+		i := k - 1
+		for i >= 0 && pick[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		pick[i]++
+		for j := i + 1; j < k; j++ {
+			pick[j] = pick[j-1] + 1
+		}
+	}
+}
+
+func containsInt(xs []int, x int) bool {
+	for _, v := range xs {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func cellsOf(idxs []int) []Cell {
+	out := make([]Cell, len(idxs))
+	for i, idx := range idxs {
+		out[i] = cellOf(idx)
+	}
+	return out
+}
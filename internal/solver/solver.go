@@ -1,94 +1,297 @@
 package solver
 
-import "time"
+import (
+	"context"
+	"math/bits"
+)
 
 // Grid matches generator's grid representation
  type Grid [9][9]uint8
 
-// Solve attempts to fill the grid in-place using backtracking.
-// Returns whether a solution was found before timeout.
-func Solve(g *Grid, timeout time.Duration) bool {
-	deadline := time.Now().Add(timeout)
-	return solveBacktrack(g, deadline)
+// Uniqueness classifies a grid by its number of solutions.
+type Uniqueness int
+
+const (
+	// Unknown means the search was cancelled before it could decide.
+	Unknown Uniqueness = iota
+	NoSolution
+	Unique
+	Multiple
+)
+
+func (u Uniqueness) String() string {
+	switch u {
+	case NoSolution:
+		return "no solution"
+	case Unique:
+		return "unique"
+	case Multiple:
+		return "multiple solutions"
+	}
+	return "unknown"
+}
+
+// CheckUniqueness searches for up to two solutions of g. It returns Unknown
+// when ctx is done first, never a guess.
+func CheckUniqueness(ctx context.Context, g Grid) Uniqueness {
+	n, err := CountSolutions(ctx, g, 2)
+	switch {
+	case err != nil:
+		return Unknown
+	case n == 0:
+		return NoSolution
+	case n == 1:
+		return Unique
+	}
+	return Multiple
+}
+
+// CheckUniquenessNodes is CheckUniqueness bounded by a number of search nodes
+// instead of a deadline, so its answer (Unknown included) is the same on every
+// machine. Seeded generation relies on that.
+func CheckUniquenessNodes(g Grid, maxNodes int) Uniqueness {
+	return CheckUniquenessNodesWith(g, nil, maxNodes)
 }
 
-// CountSolutions counts up to maxCount solutions for uniqueness check.
-func CountSolutions(g Grid, timeout time.Duration, maxCount int) int {
-	deadline := time.Now().Add(timeout)
+// CheckUniquenessNodesWith is CheckUniquenessNodes with extra regions.
+func CheckUniquenessNodesWith(g Grid, regions []Region, maxNodes int) Uniqueness {
+	s, ok := newSearchWith(context.Background(), g, regions)
+	if !ok {
+		return NoSolution
+	}
+	s.limit = maxNodes
 	count := 0
-	var dfs func(*Grid) bool
-	dfs = func(cur *Grid) bool {
-		if time.Now().After(deadline) {
-			return true
-		}
-		row, col, ok := findEmpty(*cur)
-		if !ok {
-			count++
-			return count >= maxCount
-		}
-		cands := candidates(*cur, row, col)
-		for _, v := range cands {
-			cur[row][col] = v
-			if dfs(cur) {
-				return true
-			}
-			cur[row][col] = 0
-		}
-		return false
+	s.run(func() bool {
+		count++
+		return count >= 2
+	})
+	switch {
+	case s.expired:
+		return Unknown
+	case count == 0:
+		return NoSolution
+	case count == 1:
+		return Unique
 	}
-	copyGrid := g
-	dfs(&copyGrid)
-	return count
+	return Multiple
 }
 
-func solveBacktrack(g *Grid, deadline time.Time) bool {
-	if time.Now().After(deadline) {
+// Solve attempts to fill the grid in-place using backtracking.
+// Returns whether a solution was found before ctx was done.
+func Solve(ctx context.Context, g *Grid) bool { return SolveWith(ctx, g, nil) }
+
+// SolveWith is Solve with extra regions.
+func SolveWith(ctx context.Context, g *Grid, regions []Region) bool {
+	s, ok := newSearchWith(ctx, *g, regions)
+	if !ok {
 		return false
 	}
-	row, col, ok := findEmpty(*g)
-	if !ok {
+	found := false
+	s.run(func() bool {
+		*g = s.grid()
+		found = true
 		return true
+	})
+	return found
+}
+
+// CountSolutions counts up to maxCount solutions. When ctx is done before the
+// search finishes it returns the partial count together with ctx.Err(), so a
+// cancelled search can't be mistaken for a definite answer.
+func CountSolutions(ctx context.Context, g Grid, maxCount int) (int, error) {
+	s, ok := newSearch(ctx, g)
+	if !ok {
+		return 0, nil
 	}
-	cands := candidates(*g, row, col)
-	for _, v := range cands {
-		g[row][col] = v
-		if solveBacktrack(g, deadline) {
-			return true
-		}
-		g[row][col] = 0
+	count := 0
+	s.run(func() bool {
+		count++
+		return count >= maxCount
+	})
+	if s.expired {
+		return count, ctx.Err()
 	}
-	return false
+	return count, nil
+}
+
+// Solutions returns up to max solutions of g, with ctx.Err() if the search
+// was cancelled before it could finish.
+func Solutions(ctx context.Context, g Grid, max int) ([]Grid, error) {
+	s, ok := newSearch(ctx, g)
+	if !ok {
+		return nil, nil
+	}
+	var out []Grid
+	s.run(func() bool {
+		out = append(out, s.grid())
+		return len(out) >= max
+	})
+	if s.expired {
+		return out, ctx.Err()
+	}
+	return out, nil
+}
+
+// search is a depth-first solver over row/column/box digit masks. It always
+// branches on the empty cell with the fewest candidates (MRV), which keeps
+// uniqueness checks in the microsecond range for typical puzzles.
+type search struct {
+	cells    [81]uint8
+	rows     [9]uint16
+	cols     [9]uint16
+	boxes    [9]uint16
+	empty    []int
+	ctx      context.Context
+	nodes    int
+	// limit caps nodes when > 0 (see CheckUniquenessNodes)
+	limit    int
+	expired  bool
+	// Killer cages, nil for classic grids (see killer.go)
+	cages    []cageState
+	cageOf   [81]int8
+	// digit masks of extra regions, nil for classic grids; hs maps cells to them
+	extra    []uint16
+	hs       *houseSet
+}
+
+func boxOf(r, c int) int { return (r/3)*3 + c/3 }
+
+// newSearch loads g; it reports false when the givens already clash.
+func newSearch(ctx context.Context, g Grid) (*search, bool) {
+	return newSearchWith(ctx, g, nil)
 }
 
-func findEmpty(g Grid) (int, int, bool) {
+// newSearchWith is newSearch with extra regions.
+func newSearchWith(ctx context.Context, g Grid, regions []Region) (*search, bool) {
+	s := &search{ctx: ctx}
+	if len(regions) > 0 {
+		s.extra = make([]uint16, len(regions))
+		s.hs = newHouseSet(regions)
+	}
 	for r := 0; r < 9; r++ {
 		for c := 0; c < 9; c++ {
-			if g[r][c] == 0 {
-				return r, c, true
+			v := g[r][c]
+			if v == 0 {
+				s.empty = append(s.empty, r*9+c)
+				continue
 			}
+			if v > 9 || s.free(r*9+c)&(1<<(v-1)) == 0 {
+				return nil, false
+			}
+			s.set(r*9+c, v)
+		}
+	}
+	return s, true
+}
+
+func (s *search) set(idx int, v uint8) {
+	r, c := idx/9, idx%9
+	b := uint16(1) << (v - 1)
+	s.cells[idx] = v
+	s.rows[r] |= b
+	s.cols[c] |= b
+	s.boxes[boxOf(r, c)] |= b
+	if s.cages != nil && s.cageOf[idx] >= 0 {
+		s.cages[s.cageOf[idx]].place(v)
+	}
+	if s.extra != nil {
+		for _, k := range s.hs.regionsOf[idx] {
+			s.extra[k] |= b
+		}
+	}
+}
+
+func (s *search) unset(idx int) {
+	r, c := idx/9, idx%9
+	v := s.cells[idx]
+	b := ^(uint16(1) << (v - 1))
+	s.cells[idx] = 0
+	s.rows[r] &= b
+	s.cols[c] &= b
+	s.boxes[boxOf(r, c)] &= b
+	if s.cages != nil && s.cageOf[idx] >= 0 {
+		s.cages[s.cageOf[idx]].remove(v)
+	}
+	if s.extra != nil {
+		for _, k := range s.hs.regionsOf[idx] {
+			s.extra[k] &= b
+		}
+	}
+}
+
+func (s *search) free(idx int) uint16 {
+	r, c := idx/9, idx%9
+	m := ^(s.rows[r] | s.cols[c] | s.boxes[boxOf(r, c)]) & 0x1ff
+	if s.cages != nil && s.cageOf[idx] >= 0 {
+		m &= s.cages[s.cageOf[idx]].mask
+	}
+	if s.extra != nil {
+		for _, k := range s.hs.regionsOf[idx] {
+			m &^= s.extra[k]
 		}
 	}
-	return 0, 0, false
+	return m
+}
+
+func (s *search) grid() Grid {
+	var g Grid
+	for i, v := range s.cells {
+		g[i/9][i%9] = v
+	}
+	return g
+}
+
+// timedOut polls ctx on the first node, so a cancelled ctx never yields an
+// answer, then only every 1024 nodes to keep it off the hot path.
+func (s *search) timedOut() bool {
+	if s.expired {
+		return true
+	}
+	s.nodes++
+	if s.limit > 0 && s.nodes > s.limit {
+		s.expired = true
+	} else if (s.nodes == 1 || s.nodes&1023 == 0) && s.ctx.Err() != nil {
+		s.expired = true
+	}
+	return s.expired
 }
 
-func candidates(g Grid, row, col int) []uint8 {
-	used := [10]bool{}
-	for i := 0; i < 9; i++ {
-		used[g[row][i]] = true
-		used[g[i][col]] = true
+// run calls onSolution for every solution until it returns true or ctx is done.
+// It returns true when the search was stopped early.
+func (s *search) run(onSolution func() bool) bool {
+	return s.dfs(0, onSolution)
+}
+
+// dfs fills s.empty[depth:], swapping the most constrained cell to position depth.
+func (s *search) dfs(depth int, onSolution func() bool) bool {
+	if s.timedOut() {
+		return true
 	}
-	r0 := (row / 3) * 3
-	c0 := (col / 3) * 3
-	for r := r0; r < r0+3; r++ {
-		for c := c0; c < c0+3; c++ {
-			used[g[r][c]] = true
+	if depth == len(s.empty) {
+		return onSolution()
+	}
+	best, bestCount := depth, 10
+	for i := depth; i < len(s.empty); i++ {
+		n := bits.OnesCount16(s.free(s.empty[i]))
+		if n < bestCount {
+			best, bestCount = i, n
+			if n <= 1 {
+				break
+			}
 		}
 	}
-	var out []uint8
-	for v := 1; v <= 9; v++ {
-		if !used[v] {
-			out = append(out, uint8(v))
+	if bestCount == 0 {
+		return false
+	}
+	s.empty[depth], s.empty[best] = s.empty[best], s.empty[depth]
+	idx := s.empty[depth]
+	for m := s.free(idx); m != 0; m &= m - 1 {
+		s.set(idx, uint8(bits.TrailingZeros16(m)+1))
+		stop := s.dfs(depth+1, onSolution)
+		s.unset(idx)
+		if stop {
+			return true
 		}
 	}
-	return out
+	return false
 }
@@ -0,0 +1,122 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+const (
+	uniquePuzzle = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+	// r1c1 sees 1-8 in its row and 9 in its column: consistent givens, no solution
+	deadPuzzle = ".12345678" + "9........" + "........." + "........." + "........." + "........." + "........." + "........." + "........."
+	// 5 twice in row 1
+	clashPuzzle = "5...5...." + "........." + "........." + "........." + "........." + "........." + "........." + "........." + "........."
+)
+
+func cancelled() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestCheckUniqueness(t *testing.T) {
+	var empty Grid
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		puzzle Grid
+		want   Uniqueness
+	}{
+		{"unique", context.Background(), mustGrid(t, uniquePuzzle), Unique},
+		{"empty grid", context.Background(), empty, Multiple},
+		{"dead cell", context.Background(), mustGrid(t, deadPuzzle), NoSolution},
+		{"repeated given", context.Background(), mustGrid(t, clashPuzzle), NoSolution},
+		{"cancelled", cancelled(), mustGrid(t, uniquePuzzle), Unknown},
+		{"cancelled empty grid", cancelled(), empty, Unknown},
+	}
+	for _, tt := range tests {
+		if got := CheckUniqueness(tt.ctx, tt.puzzle); got != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckUniquenessNodes(t *testing.T) {
+	var empty Grid
+	tests := []struct {
+		name   string
+		puzzle Grid
+		nodes  int
+		want   Uniqueness
+	}{
+		{"unique", mustGrid(t, uniquePuzzle), 1 << 16, Unique},
+		{"empty grid", empty, 1 << 16, Multiple},
+		{"dead cell", mustGrid(t, deadPuzzle), 1 << 16, NoSolution},
+		{"repeated given", mustGrid(t, clashPuzzle), 1 << 16, NoSolution},
+		{"over budget", mustGrid(t, uniquePuzzle), 3, Unknown},
+		{"empty over budget", empty, 10, Unknown},
+	}
+	for _, tt := range tests {
+		if got := CheckUniquenessNodes(tt.puzzle, tt.nodes); got != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	var empty Grid
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		puzzle  Grid
+		max     int
+		want    int
+		wantErr error
+	}{
+		{"empty grid stops at the limit", context.Background(), empty, 5, 5, nil},
+		{"limit of one", context.Background(), empty, 1, 1, nil},
+		{"unique", context.Background(), mustGrid(t, uniquePuzzle), 5, 1, nil},
+		{"dead cell", context.Background(), mustGrid(t, deadPuzzle), 5, 0, nil},
+		{"repeated given", context.Background(), mustGrid(t, clashPuzzle), 5, 0, nil},
+		{"cancelled", cancelled(), empty, 1 << 20, 0, context.Canceled},
+	}
+	for _, tt := range tests {
+		n, err := CountSolutions(tt.ctx, tt.puzzle, tt.max)
+		if n != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("CountSolutions %s: %d, %v; want %d, %v", tt.name, n, err, tt.want, tt.wantErr)
+		}
+		sols, err := Solutions(tt.ctx, tt.puzzle, tt.max)
+		if len(sols) != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Solutions %s: %d, %v; want %d, %v", tt.name, len(sols), err, tt.want, tt.wantErr)
+		}
+		seen := map[Grid]bool{}
+		for _, s := range sols {
+			if seen[s] || !isSolution(s, tt.puzzle) {
+				t.Errorf("Solutions %s: bad or repeated grid %v", tt.name, s)
+			}
+			seen[s] = true
+		}
+	}
+}
+
+// isSolution reports whether s is a full valid grid that keeps the givens of g.
+func isSolution(s, g Grid) bool {
+	var rows, cols, boxes [9]uint16
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			v := s[r][c]
+			if v < 1 || v > 9 || (g[r][c] != 0 && g[r][c] != v) {
+				return false
+			}
+			b := uint16(1) << (v - 1)
+			if rows[r]&b != 0 || cols[c]&b != 0 || boxes[boxOf(r, c)]&b != 0 {
+				return false
+			}
+			rows[r] |= b
+			cols[c] |= b
+			boxes[boxOf(r, c)] |= b
+		}
+	}
+	return true
+}
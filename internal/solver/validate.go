@@ -0,0 +1,52 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidGivens     = errors.New("givens break the rules")
+	ErrNoSolution        = errors.New("puzzle has no solution")
+	ErrMultipleSolutions = errors.New("puzzle has more than one solution")
+	ErrUndecided         = errors.New("could not verify the puzzle in time")
+)
+
+// Validate checks that g is a proper puzzle: consistent givens and exactly
+// one solution. The returned error wraps one of the Err* values above.
+func Validate(ctx context.Context, g Grid) error {
+	if err := checkGivens(g); err != nil {
+		return err
+	}
+	switch CheckUniqueness(ctx, g) {
+	case NoSolution:
+		return ErrNoSolution
+	case Multiple:
+		return ErrMultipleSolutions
+	case Unknown:
+		return ErrUndecided
+	}
+	return nil
+}
+
+// checkGivens reports the first digit that repeats within a house.
+func checkGivens(g Grid) error {
+	for h := 0; h < 27; h++ {
+		var seen [10]int
+		for _, idx := range classicHouses.cells[h] {
+			v := g[idx/9][idx%9]
+			if v == 0 {
+				continue
+			}
+			if v > 9 {
+				return fmt.Errorf("%w: %s holds %d", ErrInvalidGivens, cellOf(idx), v)
+			}
+			if seen[v] != 0 {
+				return fmt.Errorf("%w: %d repeats in %s (%s, %s)", ErrInvalidGivens, v, classicHouses.list[h], cellOf(seen[v]-1), cellOf(idx))
+			}
+			seen[v] = idx + 1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"punkdoku/internal/generator"
+)
+
+// DailyEntry summarises the games played on one day's daily puzzle.
+type DailyEntry struct {
+	Solved bool
+	// OnTime is true when it was solved on its own (UTC) day; only those count for streaks.
+	OnTime bool
+	// Best is the fastest timed solve, zero when none was timed.
+	Best time.Duration
+}
+
+// Daily indexes daily games by their DailySeed date (YYYY-MM-DD).
+func (s Store) Daily() map[string]DailyEntry {
+	out := map[string]DailyEntry{}
+	for _, g := range s.Games {
+		if g.Difficulty != "Daily" || !g.Won { continue }
+		e := out[g.Seed]
+		e.Solved = true
+		if generator.DailySeed(g.Date) == g.Seed {
+			e.OnTime = true
+		}
+		if g.Timed && (e.Best == 0 || g.Elapsed < e.Best) {
+			e.Best = g.Elapsed
+		}
+		out[g.Seed] = e
+	}
+	return out
+}
+
+// Streaks returns the current and longest run of consecutive days whose daily
+// was solved on the day. Today's unsolved daily doesn't break the current
+// streak yet.
+func (s Store) Streaks(today time.Time) (current, longest int) {
+	days := s.Daily()
+	onTime := func(t time.Time) bool { return days[generator.DailySeed(t)].OnTime }
+
+	day := today.UTC()
+	if !onTime(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	for onTime(day) {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	run := 0
+	var prev time.Time
+	for _, t := range sortedDays(days) {
+		if !days[generator.DailySeed(t)].OnTime { continue }
+		if run > 0 && prev.AddDate(0, 0, 1).Equal(t) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = t
+		if run > longest { longest = run }
+	}
+	return current, longest
+}
+
+// sortedDays parses the date keys in ascending order, skipping malformed ones.
+func sortedDays(days map[string]DailyEntry) []time.Time {
+	var out []time.Time
+	for k := range days {
+		if t, err := time.Parse("2006-01-02", k); err == nil {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"punkdoku/internal/generator"
+)
+
+// Record is one finished or abandoned game.
+type Record struct {
+	Difficulty string        `json:"difficulty"`
+	Seed       string        `json:"seed"`
+	Elapsed    time.Duration `json:"elapsed"`
+	// Timed is false when the timer was off, so Elapsed is not a real time.
+	Timed    bool      `json:"timed"`
+	Hints    int       `json:"hints"`
+	Mistakes int       `json:"mistakes"`
+	Won      bool      `json:"won"`
+	Date     time.Time `json:"date"`
+	// Tier is the difficulty of a daily puzzle; empty for other games.
+	Tier string `json:"tier,omitempty"`
+}
+
+// Label is the row a record is summarised under: its difficulty, or
+// "Daily <tier>" for dailies. Dailies from before the weekly rotation have no
+// tier and were all Normal.
+func (r Record) Label() string {
+	if r.Difficulty != "Daily" { return r.Difficulty }
+	if r.Tier == "" { return "Daily Normal" }
+	return "Daily " + r.Tier
+}
+
+// Store is the on-disk list of every recorded game.
+type Store struct {
+	Games []Record `json:"games"`
+}
+
+// Summary aggregates the records of one difficulty. Times only count timed wins.
+type Summary struct {
+	Played  int
+	Won     int
+	Best    time.Duration
+	Average time.Duration
+	Median  time.Duration
+}
+
+// WinRate is Won/Played in percent.
+func (s Summary) WinRate() int {
+	if s.Played == 0 { return 0 }
+	return s.Won * 100 / s.Played
+}
+
+func path() (string, error) {
+	h, err := os.UserHomeDir()
+	if err != nil { return "", err }
+	return filepath.Join(h, ".punkdoku", "stats.json"), nil
+}
+
+// Load reads the stats store; a missing file is an empty store.
+func Load() (Store, error) {
+	var s Store
+	p, err := path()
+	if err != nil { return s, err }
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+	if err := json.Unmarshal(b, &s); err != nil { return s, err }
+	return s, nil
+}
+
+// Save writes the stats store.
+func Save(s Store) error {
+	p, err := path()
+	if err != nil { return err }
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { return err }
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil { return err }
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil { return err }
+	return os.Rename(tmp, p)
+}
+
+// Add appends r and reports whether it beat the previous best time of its
+// label (the first timed win counts as a record too). Dailies get their tier
+// filled in from the seed date.
+func (s *Store) Add(r Record) (newBest bool) {
+	if r.Difficulty == "Daily" && r.Tier == "" {
+		if d, err := time.Parse("2006-01-02", r.Seed); err == nil {
+			r.Tier = generator.DailyDifficulty(d).String()
+		}
+	}
+	if r.Won && r.Timed {
+		prev := s.Summary(r.Label())
+		newBest = prev.Best == 0 || r.Elapsed < prev.Best
+	}
+	s.Games = append(s.Games, r)
+	return newBest
+}
+
+// Add loads the store, appends r and saves it again.
+func Add(r Record) (newBest bool, err error) {
+	s, err := Load()
+	if err != nil { return false, err }
+	newBest = s.Add(r)
+	return newBest, Save(s)
+}
+
+// Summary aggregates the games of one label (see Record.Label).
+func (s Store) Summary(label string) Summary {
+	var sum Summary
+	var times []time.Duration
+	for _, g := range s.Games {
+		if g.Label() != label { continue }
+		sum.Played++
+		if !g.Won { continue }
+		sum.Won++
+		if g.Timed {
+			times = append(times, g.Elapsed)
+		}
+	}
+	if len(times) == 0 { return sum }
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	var total time.Duration
+	for _, t := range times { total += t }
+	sum.Best = times[0]
+	sum.Average = total / time.Duration(len(times))
+	if n := len(times); n%2 == 1 {
+		sum.Median = times[n/2]
+	} else {
+		sum.Median = (times[n/2-1] + times[n/2]) / 2
+	}
+	return sum
+}
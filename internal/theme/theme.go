@@ -19,6 +19,8 @@ type Palette struct {
 	CellSelectedFG string // 선택된 셀 문자 색상
 	CellDuplicateBG string
 	CellConflictBG string
+	CellHintBG string
+	CellRegionBG string // X-Sudoku/Hyper 추가 영역의 옅은 음영
 	Accent string
 }
 
@@ -44,6 +46,8 @@ func Light() Theme {
 			CellSelectedFG:  "#000000",  // 선택된 셀 텍스트 검은색
 			CellDuplicateBG: "#fff2cc",  // 중복은 배경 유지
 			CellConflictBG:  "#ffd6d6",  // 충돌은 배경 유지
+			CellHintBG:      "#d9f99d",  // 힌트 셀 연두색
+			CellRegionBG:    "#f1f1f4",  // 추가 영역은 아주 옅은 회색
 			Accent:          "#ff6600",  // 주황색 액센트
 		},
 	}
@@ -67,6 +71,8 @@ func Punk() Theme {
 			CellSelectedFG:  "#00e5ff",  // 사이언 (선택된 셀)
 			CellDuplicateBG: "#3d2d0a",
 			CellConflictBG:  "#5b1515",
+			CellHintBG:      "#1f3d14",
+			CellRegionBG:    "#12161d",
 			Accent:          "#00e5ff",
 		},
 	}
@@ -138,6 +144,8 @@ func (ac AdaptiveColors) GetDifficultyColors() map[string]string {
 			"Normal":    "#16a34a", // darker green for light bg
 			"Hard":      "#dc2626", // red for light bg
 			"Lunatic": "#7c2d92", // purple for light bg
+			"Minimal":   "#475569", // slate for light bg
+			"Killer":    "#b91c1c", // crimson for light bg
 			"Daily":     "#16a34a", // darker green for light bg
 		}
 	}
@@ -147,6 +155,8 @@ func (ac AdaptiveColors) GetDifficultyColors() map[string]string {
 		"Normal":    "#22c55e", // green
 		"Hard":      "#f59e0b", // orange
 		"Lunatic": "#7c3aed", // violet
+		"Minimal":   "#94a3b8", // slate
+		"Killer":    "#f43f5e", // rose
 		"Daily":     "#22c55e", // green
 	}
 }
@@ -161,6 +171,8 @@ func (ac AdaptiveColors) GetGradientColors() map[string][2]string {
 			"daily":     {"#16a34a", "#eab308"}, // green to yellow for light bg
 			"hard":      {"#dc2626", "#991b1b"}, // red gradient for light bg
 			"lunatic": {"#7c2d92", "#be185d"}, // purple to pink for light bg
+			"minimal":   {"#475569", "#0f172a"}, // slate gradient for light bg
+			"killer":    {"#b91c1c", "#7c2d92"}, // crimson to purple for light bg
 			"complete":  {"#7c2d92", "#be185d"}, // success gradient for light bg
 		}
 	}
@@ -172,6 +184,8 @@ func (ac AdaptiveColors) GetGradientColors() map[string][2]string {
 		"daily":     {"#22c55e", "#facc15"}, // green to yellow for dark bg
 		"hard":      {"#f59e0b", "#ef4444"}, // orange to red
 		"lunatic": {"#7c3aed", "#ec4899"}, // violet to pink
+		"minimal":   {"#94a3b8", "#e2e8f0"}, // slate to silver
+		"killer":    {"#f43f5e", "#f97316"}, // rose to orange
 		"complete":  {"#7c3aed", "#ec4899"}, // violet to pink
 	}
 }
@@ -4,14 +4,25 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"punkdoku/internal/config"
+	"punkdoku/internal/game"
 	"punkdoku/internal/generator"
+	"punkdoku/internal/pool"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/save"
+	"punkdoku/internal/sharecode"
+	"punkdoku/internal/stats"
 	"punkdoku/internal/theme"
 )
 
@@ -20,8 +31,27 @@ type appState int
 const (
 	stateMenu appState = iota
 	stateGame
+	stateImport
+	stateStats
+	stateCalendar
+	stateLoading
 )
 
+// entryKind is what the text-entry screen (stateImport) reads.
+type entryKind int
+
+const (
+	entryPuzzle entryKind = iota // puzzle string or file
+	entryCode                    // share code
+	entrySeed                    // seed word for seedDiff
+)
+
+var errNoSeed = errors.New("enter a seed word")
+
+// difficultyItems are the menu entries that start a new puzzle; any further
+// menu entries are rendered on a separate row below them.
+var difficultyItems = []string{"Easy", "Normal", "Hard", "Lunatic", "Daily"}
+
 type App struct {
 	state         appState
 	cfg           config.Config
@@ -32,29 +62,122 @@ type App struct {
 	selectedIdx   int
 	autoCheck     bool
 	timerEnabled  bool
+	symmetry      generator.Symmetry
+	variant       game.Variant
 
 	width         int
 	height        int
 
 	currentDiff   string
+	currentSeed   string
+	currentSym    generator.Symmetry
+	currentVar    game.Variant
 	game          Model
+
+	importInput   textinput.Model
+	importErr     string
+	entry         entryKind
+	seedDiff      string
+
+	// background generation: the latest request, its id and a failure to show
+	spinner       spinner.Model
+	pending       genRequest
+	genID         int
+	genErr        string
+	cancelGen     context.CancelFunc
+	// pool holds pre-generated puzzles so menu games start instantly
+	pool          *pool.Pool
+
+	streak        int
+	bestStreak    int
+	calDay        time.Time
 }
 
 func NewApp(cfg config.Config) App {
 	th := theme.DetectTheme()
-	return App{
+	ti := textinput.New()
+	ti.Placeholder = "81 chars ('.' or '0' = blank) or a puzzle file"
+	ti.CharLimit = 512
+	ti.Width = 50
+	a := App{
 		state:        stateMenu,
 		cfg:          cfg,
 		th:           th,
 		styles:       BuildStyles(th),
-		menuItems:    []string{"Easy", "Normal", "Hard", "Lunatic", "Daily"},
 		selectedIdx:  1,
 		autoCheck:    cfg.AutoCheck,
 		timerEnabled: cfg.TimerEnabled,
+		importInput:  ti,
+		pool:         pool.New(3),
+		spinner:      spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(BuildStyles(th).MenuItem)),
+	}
+	a.symmetry, _ = generator.ParseSymmetry(cfg.Symmetry)
+	a.variant, _ = game.ParseVariant(cfg.Variant)
+	a.refreshMenu()
+	return a
+}
+
+// NewAppWithPuzzle opens straight into a game of the given custom puzzle,
+// labeled "Custom"; the puzzle should already be validated by ImportPuzzle.
+func NewAppWithPuzzle(cfg config.Config, p puzzleio.Puzzle) App {
+	a := NewApp(cfg)
+	a.game, _ = a.startCustom(p)
+	a.state = stateGame
+	return a
+}
+
+// NewAppWithCode opens straight into the game of a share code. The puzzle is
+// generated up front, before the program starts.
+func NewAppWithCode(cfg config.Config, code string) (App, error) {
+	a := NewApp(cfg)
+	c, err := sharecode.Parse(code)
+	if err != nil { return a, err }
+	if c.Kind == sharecode.Givens {
+		p, err := ImportPuzzle(game.Grid(c.Givens).String())
+		if err != nil { return a, err }
+		a.game, _ = a.startCustom(p)
+	} else {
+		r := codeRequest(c)
+		p, err := r.generate(context.Background())
+		if err != nil { return a, err }
+		a.game, _ = a.begin(r, p)
+	}
+	a.state = stateGame
+	return a, nil
+}
+
+// menuEntries lists the difficulties, the "Minimal" and "Killer" puzzle modes, "Continue"
+// when a saved game exists, "Import" for custom puzzles, "Code" for share
+// codes, the "Stats" screen and the daily "Calendar".
+func menuEntries() []string {
+	items := append(append([]string{}, difficultyItems...), "Minimal", "Killer")
+	if save.Exists() {
+		items = append(items, "Continue")
 	}
+	items = append(items, "Import", "Code", "Stats", "Calendar")
+	return items
 }
 
-func (a App) Init() tea.Cmd { return nil }
+// refreshMenu re-reads the save and stats files whenever the menu is shown.
+func (a *App) refreshMenu() {
+	a.menuItems = menuEntries()
+	a.selectedIdx = clamp(a.selectedIdx, 0, len(a.menuItems)-1)
+	if st, err := stats.Load(); err == nil {
+		a.streak, a.bestStreak = st.Streaks(time.Now())
+	}
+}
+
+// Close stops the background puzzle generation; call it once the program
+// has exited.
+func (a App) Close() { a.pool.Close() }
+
+func (a App) Init() tea.Cmd {
+	a.pool.Fill(context.Background(), a.symmetry)
+	if a.state == stateGame {
+		return a.game.Init()
+	}
+	return nil
+}
 
 func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch a.state {
@@ -75,11 +198,32 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.autoCheck = !a.autoCheck
 			case "t":
 				a.timerEnabled = !a.timerEnabled
+			case "y":
+				a.symmetry = (a.symmetry + 1) % (generator.FourFold + 1)
+				a.pool.Fill(context.Background(), a.symmetry)
+			case "v":
+				a.variant = (a.variant + 1) % (game.Hyper + 1)
+			case "s":
+				// a seed word for the highlighted difficulty, shared by everyone typing it
+				sel := a.menuItems[a.selectedIdx]
+				if !seeded(sel) { return a, nil }
+				a.seedDiff = sel
+				return a, a.openEntry(entrySeed)
 			case "enter":
-				gm, cmd := a.startGame()
-				a.game = gm
-				a.state = stateGame
-				return a, cmd
+				switch a.menuItems[a.selectedIdx] {
+				case "Stats":
+					a.state = stateStats
+					return a, nil
+				case "Calendar":
+					a.state = stateCalendar
+					a.calDay = time.Now().UTC()
+					return a, nil
+				case "Import":
+					return a, a.openEntry(entryPuzzle)
+				case "Code":
+					return a, a.openEntry(entryCode)
+				}
+				return a, a.startGame()
 			case "q", "esc", "ctrl+c":
 				return a, tea.Quit
 			}
@@ -87,16 +231,85 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.width, a.height = m.Width, m.Height
 		}
 		return a, nil
+	case stateCalendar:
+		return a.updateCalendar(msg)
+	case stateLoading:
+		return a.updateLoading(msg)
+	case stateStats:
+		switch m := msg.(type) {
+		case tea.KeyMsg:
+			switch m.String() {
+			case "ctrl+c":
+				return a, tea.Quit
+			case "esc", "enter", "q", "m":
+				a.state = stateMenu
+			}
+		case tea.WindowSizeMsg:
+			a.width, a.height = m.Width, m.Height
+		}
+		return a, nil
+	case stateImport:
+		switch m := msg.(type) {
+		case tea.KeyMsg:
+			switch m.String() {
+			case "esc":
+				a.importInput.Blur()
+				a.state = stateMenu
+				return a, nil
+			case "ctrl+c":
+				return a, tea.Quit
+			case "enter":
+				var cmd tea.Cmd
+				var err error
+				switch a.entry {
+				case entryCode:
+					cmd, err = a.playCode(a.importInput.Value())
+				case entrySeed:
+					if seed := strings.TrimSpace(a.importInput.Value()); seed == "" {
+						err = errNoSeed
+					} else {
+						cmd = a.requestPuzzle(a.newRequest(a.seedDiff, seed))
+					}
+				default:
+					var p puzzleio.Puzzle
+					if p, err = ImportPuzzle(a.importInput.Value()); err == nil {
+						a.game, cmd = a.startCustom(p)
+						a.state = stateGame
+					}
+				}
+				if err != nil {
+					a.importErr = err.Error()
+					return a, nil
+				}
+				a.importInput.Blur()
+				return a, cmd
+			}
+		case tea.WindowSizeMsg:
+			a.width, a.height = m.Width, m.Height
+		}
+		var cmd tea.Cmd
+		a.importInput, cmd = a.importInput.Update(msg)
+		return a, cmd
 	case stateGame:
-		// intercept main menu key
+		// intercept main menu / quit keys so the game is saved first
 		if kmsg, isKey := msg.(tea.KeyMsg); isKey {
-			if kmsg.String() == "m" {
+			switch kmsg.String() {
+			case "m":
+				a.persist()
 				a.state = stateMenu
+				a.refreshMenu()
+				a.pool.Fill(context.Background(), a.symmetry)
 				return a, nil
+			case "q", "esc", "ctrl+c":
+				a.persist()
+				return a, tea.Quit
 			}
 		}
 		gm, cmd := a.game.Update(msg)
 		if v, ok := gm.(Model); ok { a.game = v }
+		if a.game.Completed() && !a.game.recorded {
+			a.recordFinish()
+		}
 		return a, cmd
 	}
 	return a, nil
@@ -108,32 +321,241 @@ func (a App) View() string {
 		return a.viewMenu()
 	case stateGame:
 		return a.viewGame()
+	case stateImport:
+		return a.viewImport()
+	case stateStats:
+		return a.viewStats()
+	case stateCalendar:
+		return a.viewCalendar()
+	case stateLoading:
+		return a.viewLoading()
 	}
 	return ""
 }
 
-func (a *App) startGame() (Model, tea.Cmd) {
-	var g generator.Grid
-	var err error
+// persist writes the running game to the save file, or clears the save once
+// the puzzle is solved so "Continue" disappears from the menu.
+func (a App) persist() {
+	if a.game.Completed() {
+		_ = save.Clear()
+		return
+	}
+	sg := a.game.Snapshot(a.currentDiff, a.currentSeed)
+	if a.currentSym != generator.NoSymmetry {
+		sg.Symmetry = a.currentSym.String()
+	}
+	if a.currentVar != game.Classic {
+		sg.Variant = a.currentVar.String()
+	}
+	_ = save.Write(sg)
+}
+
+// recordFinish stores the solved game in the stats (once) and flags a new
+// personal best for the completion banner.
+func (a *App) recordFinish() {
+	a.game.recorded = true
+	best, err := stats.Add(a.game.record(a.currentDiff, a.currentSeed, true))
+	a.game.newBest = err == nil && best
+	_ = save.Clear()
+}
+
+// abandonSaved counts a saved game the player started but didn't solve as a
+// loss before a new game replaces it.
+func abandonSaved() {
+	sg, err := save.Load()
+	if err != nil { return }
+	if !sg.Started() || sg.Solved() {
+		_ = save.Clear()
+		return
+	}
+	_, _ = stats.Add(stats.Record{
+		Difficulty: sg.Difficulty,
+		Seed:       sg.Seed,
+		Elapsed:    sg.Elapsed,
+		Hints:      sg.HintsUsed,
+		Mistakes:   sg.Mistakes,
+		Date:       time.Now(),
+	})
+	_ = save.Clear()
+}
+
+// newSeed returns a fresh seed for non-daily games so they can be saved and replayed.
+func newSeed() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func (a *App) gameConfig() config.Config {
+	cfg := a.cfg
+	cfg.AutoCheck = a.autoCheck
+	cfg.TimerEnabled = a.timerEnabled
+	return cfg
+}
+
+// startGame begins the highlighted menu entry: a saved game resumes at once,
+// new puzzles come from the pool or are generated in the background (see
+// requestPuzzle).
+func (a *App) startGame() tea.Cmd {
 	sel := a.menuItems[a.selectedIdx]
 	switch sel {
+	case "Continue":
+		gm, cmd := a.continueGame()
+		a.game = gm
+		a.state = stateGame
+		return cmd
 	case "Daily":
-		g, err = generator.GenerateDaily(time.Now())
-	case "Easy":
-		g, err = generator.Generate(generator.Easy, "")
-	case "Normal":
-		g, err = generator.Generate(generator.Normal, "")
-	case "Hard":
-		g, err = generator.Generate(generator.Hard, "")
-	case "Lunatic":
-		g, err = generator.Generate(generator.Lunatic, "")
+		return a.requestPuzzle(dailyRequest(time.Now()))
 	}
+	// the pool only keeps classic puzzles
+	if d, err := generator.ParseDifficulty(sel); err == nil && a.variant == game.Classic {
+		if e, ok := a.pool.Take(d, a.symmetry); ok {
+			var cmd tea.Cmd
+			a.game, cmd = a.begin(a.newRequest(sel, e.Seed), generated{grid: e.Grid})
+			a.state = stateGame
+			return cmd
+		}
+	}
+	r := a.newRequest(sel, newSeed())
+	r.fresh = true
+	return a.requestPuzzle(r)
+}
+
+// newRequest is the request for menu entry sel with seed, using the menu's
+// symmetry and variant where they apply.
+func (a *App) newRequest(sel, seed string) genRequest {
+	r := genRequest{label: sel, seed: seed}
+	if _, err := generator.ParseDifficulty(sel); err == nil {
+		r.sym, r.variant = a.symmetry, a.variant
+	}
+	return r
+}
+
+// seeded reports whether menu entry sel generates from a seed word: the
+// difficulties and Killer.
+func seeded(sel string) bool {
+	_, err := generator.ParseDifficulty(sel)
+	return err == nil || sel == "Killer"
+}
+
+// openEntry switches to the text-entry screen for kind.
+func (a *App) openEntry(kind entryKind) tea.Cmd {
+	a.state = stateImport
+	a.entry = kind
+	a.importErr = ""
+	a.importInput.SetValue("")
+	a.importInput.CharLimit = 512
+	switch kind {
+	case entryCode:
+		a.importInput.Placeholder = "share code, e.g. H1-lq3z8f2kab"
+	case entrySeed:
+		a.importInput.Placeholder = "any word, e.g. friday-lunch"
+		a.importInput.CharLimit = 32 // fits the game header
+	default:
+		a.importInput.Placeholder = "81 chars ('.' or '0' = blank) or a puzzle file"
+	}
+	return a.importInput.Focus()
+}
+
+// begin starts a game on the puzzle generated for r. The same label, seed and
+// symmetry give everyone the same board.
+func (a *App) begin(r genRequest, p generated) (Model, tea.Cmd) {
+	abandonSaved()
+	a.currentDiff = r.label
+	a.currentSeed = r.seed
+	a.currentSym = r.sym
+	a.currentVar = r.variant
+	var m Model
+	if p.killer != nil {
+		m = NewKiller(*p.killer, a.th, a.gameConfig())
+	} else {
+		m = NewVariant(p.grid, r.variant, a.th, a.gameConfig())
+	}
+	m = a.decorate(m, r.label)
+	return m, m.Init()
+}
+
+// dailyTier names the rotation tier of a daily seed (its YYYY-MM-DD date).
+func dailyTier(seed string) string {
+	d, err := time.Parse("2006-01-02", seed)
+	if err != nil { return "" }
+	return generator.DailyDifficulty(d).String()
+}
+
+// startCustom starts a game from an imported puzzle, keeping any values and
+// pencil marks the file carried.
+func (a *App) startCustom(p puzzleio.Puzzle) (Model, tea.Cmd) {
+	a.currentDiff = "Custom"
+	a.currentSeed = ""
+	a.currentSym = generator.NoSymmetry
+	a.currentVar = game.Classic
+	abandonSaved()
+	m := New(p.Grid(), a.th, a.gameConfig())
+	m.board = p.Board
+	m.completed = isSolved(m.board.Values, m.solution)
+	// a file that arrives solved is already finished: no win to record
+	m.recorded = m.completed
+	m = a.decorate(m, "Custom")
+	return m, m.Init()
+}
+
+// playCode starts the exact game a share code describes: seeded and daily
+// codes are regenerated and keep their difficulty label (and stats row),
+// givens codes are validated and played as Custom.
+func (a *App) playCode(input string) (tea.Cmd, error) {
+	c, err := sharecode.Parse(input)
+	if err != nil { return nil, err }
+	if c.Kind == sharecode.Givens {
+		p, err := ImportPuzzle(game.Grid(c.Givens).String())
+		if err != nil { return nil, err }
+		var cmd tea.Cmd
+		a.game, cmd = a.startCustom(p)
+		a.state = stateGame
+		return cmd, nil
+	}
+	return a.requestPuzzle(codeRequest(c)), nil
+}
+
+// codeRequest is the generation request of a seeded, daily or Killer share code.
+func codeRequest(c sharecode.Code) genRequest {
+	switch c.Kind {
+	case sharecode.Daily:
+		return dailyRequest(c.Date())
+	case sharecode.Killer:
+		return genRequest{label: "Killer", seed: c.Seed}
+	}
+	return genRequest{label: c.Difficulty.String(), seed: c.Seed, sym: c.Symmetry, variant: c.Variant}
+}
+
+// shareCode is the code of the running game: its recipe when it was
+// generated from a seed, otherwise its givens.
+func (a App) shareCode() string {
+	if a.currentSeed != "" {
+		if a.currentDiff == "Daily" {
+			if d, err := time.Parse("2006-01-02", a.currentSeed); err == nil {
+				return sharecode.ForDaily(d)
+			}
+		} else if a.currentDiff == "Killer" {
+			return sharecode.ForKiller(a.currentSeed)
+		} else if d, err := generator.ParseDifficulty(a.currentDiff); err == nil {
+			return sharecode.ForSeed(d, a.currentSym, a.currentVar, a.currentSeed)
+		}
+	}
+	return sharecode.ForGivens(generator.Grid(a.game.Givens()))
+}
+
+// continueGame restores the saved game from ~/.punkdoku/save.json.
+func (a *App) continueGame() (Model, tea.Cmd) {
+	sg, err := save.Load()
 	if err != nil { return a.game, nil }
-	cfg := a.cfg
-	cfg.AutoCheck = a.autoCheck
-	cfg.TimerEnabled = a.timerEnabled
-	a.currentDiff = sel
-	m := New(g, a.th, cfg)
+	a.currentDiff = sg.Difficulty
+	a.currentSeed = sg.Seed
+	a.currentSym, _ = generator.ParseSymmetry(sg.Symmetry)
+	a.currentVar, _ = game.ParseVariant(sg.Variant)
+	m := a.decorate(Resume(sg, a.th, a.gameConfig()), sg.Difficulty)
+	return m, m.Init()
+}
+
+// decorate tints the board separators and givens with the difficulty color.
+func (a *App) decorate(m Model, sel string) Model {
 	// 적응형 색상 사용
 	adaptiveColors := theme.NewAdaptiveColors(a.th)
 	diffColors := adaptiveColors.GetDifficultyColors()
@@ -146,7 +568,7 @@ func (a *App) startGame() (Model, tea.Cmd) {
 	m.styles.ColSep = style
 	// Fixed 숫자도 구분선과 동일한 색상 사용
 	m.styles.CellFixed = m.styles.CellFixed.Foreground(lipgloss.Color(hex))
-	return m, m.Init()
+	return m
 }
 
 func (a App) viewMenu() string {
@@ -161,27 +583,44 @@ func (a App) viewMenu() string {
 	// Options
 	optAC := fmt.Sprintf("Auto-Check (a): %s", boolText(a.styles, a.autoCheck))
 	optTM := fmt.Sprintf("Timer (t): %s", boolText(a.styles, a.timerEnabled))
+	optSY := fmt.Sprintf("Symmetry (y): %s", a.styles.MenuItem.Render(a.symmetry.String()))
+	optVR := fmt.Sprintf("Variant (v): %s", a.styles.MenuItem.Render(a.variant.String()))
+	optST := a.styles.MenuItem.Render(fmt.Sprintf("Daily streak: %d (best %d)", a.streak, a.bestStreak))
 
 	// Adaptive colors
 	adaptiveColors := theme.NewAdaptiveColors(a.th)
 	accentColors := adaptiveColors.GetAccentColors()
 	
-	// Difficulty list (horizontal), Daily last
-	diffs := []string{"Easy", "Normal", "Hard", "Lunatic", "Daily"}
-	var items []string
+	// Difficulty list (horizontal), Daily last; extra entries go on their own row
+	var items, extras []string
 	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(accentColors["selected"])).Bold(true)
-	for i, name := range diffs {
+	for i, name := range a.menuItems {
 		prefix := "  "
 		if i == a.selectedIdx { prefix = "✭ " }
 		label := prefix + name
+		rendered := a.styles.MenuItem.Render(label)
 		if i == a.selectedIdx {
-			items = append(items, selectedStyle.Render(label))
+			rendered = selectedStyle.Render(label)
+		}
+		if i < len(difficultyItems) {
+			items = append(items, rendered)
 		} else {
-			items = append(items, a.styles.MenuItem.Render(label))
+			extras = append(extras, rendered)
 		}
 	}
 	gap := strings.Repeat(" ", 4)
 	diffRow := strings.Join(items, gap)
+	// extras wrap onto as many centered rows as the box width needs
+	rowWidth := lipgloss.Width(diffRow) + 6
+	extraRow := ""
+	for len(extras) > 0 {
+		n := 1
+		for n < len(extras) && lipgloss.Width(strings.Join(extras[:n+1], gap)) <= rowWidth {
+			n++
+		}
+		extraRow += "\n" + lipgloss.PlaceHorizontal(rowWidth, lipgloss.Center, strings.Join(extras[:n], gap))
+		extras = extras[n:]
+	}
 
 	// Adaptive gradient colors
 	gradientColors := adaptiveColors.GetGradientColors()
@@ -189,7 +628,7 @@ func (a App) viewMenu() string {
 	leftHex := bannerGrad[0]
 	rightHex := bannerGrad[1]
 	
-	title := gradientText("Select difficulty", leftHex, rightHex)
+	title := gradientText("Select difficulty", leftHex, rightHex) + a.styles.Status.Render("  (s: enter a seed)")
 	box := renderGradientBox(diffRow, 2, leftHex, rightHex)  // 4에서 2로 줄임
 
 	// Gradient banner (line by line)
@@ -201,7 +640,65 @@ func (a App) viewMenu() string {
 	gradientBanner := gb.String()
 
 	// Compose content with explicit 2-line top/bottom padding
-	content := "\n\n" + gradientBanner + "\n\n\n" + optAC + "\n" + optTM + "\n\n\n" + title + "\n" + box + "\n\n"
+	content := "\n\n" + gradientBanner + "\n\n\n" + optAC + "\n" + optTM + "\n" + optSY + "\n" + optVR + "\n" + optST + "\n\n\n" + title + "\n" + box + extraRow + "\n\n"
+	if a.genErr != "" {
+		content += a.styles.StatusError.Width(58).Render(a.genErr) + "\n\n"
+	}
+	panel := a.styles.Panel.Render(content)
+	if a.width > 0 && a.height > 0 {
+		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
+	}
+	return a.styles.App.Render(panel)
+}
+
+func (a App) viewImport() string {
+	adaptiveColors := theme.NewAdaptiveColors(a.th)
+	bannerGrad := adaptiveColors.GetGradientColors()["banner"]
+	title := gradientText("Import puzzle", bannerGrad[0], bannerGrad[1])
+	switch a.entry {
+	case entryCode:
+		title = gradientText("Enter share code", bannerGrad[0], bannerGrad[1])
+	case entrySeed:
+		title = gradientText("Seed for "+a.seedDiff, bannerGrad[0], bannerGrad[1])
+	}
+	help := a.styles.Status.Render("Enter: play · Esc: back")
+	errLine := ""
+	if a.importErr != "" {
+		errLine = "\n\n" + a.styles.StatusError.Width(54).Render(a.importErr)
+	}
+	content := "\n" + title + "\n\n" + a.importInput.View() + errLine + "\n\n" + help + "\n"
+	panel := a.styles.Panel.Render(content)
+	if a.width > 0 && a.height > 0 {
+		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
+	}
+	return a.styles.App.Render(panel)
+}
+
+func (a App) viewStats() string {
+	adaptiveColors := theme.NewAdaptiveColors(a.th)
+	bannerGrad := adaptiveColors.GetGradientColors()["banner"]
+	title := gradientText("Stats", bannerGrad[0], bannerGrad[1])
+	st, err := stats.Load()
+	var b strings.Builder
+	b.WriteString(a.styles.Status.Render(fmt.Sprintf("%-13s %6s %4s %5s %6s %6s %6s", "", "Played", "Won", "Rate", "Best", "Avg", "Median")))
+	rows := append(append([]string{}, difficultyItems[:4]...), "Minimal", "Killer")
+	for d := generator.Easy; d <= generator.Lunatic; d++ {
+		rows = append(rows, "Daily "+d.String())
+	}
+	for _, diff := range append(rows, "Custom") {
+		sum := st.Summary(diff)
+		best, avg, med := "--:--", "--:--", "--:--"
+		if sum.Best > 0 {
+			best, avg, med = formatClock(sum.Best), formatClock(sum.Average), formatClock(sum.Median)
+		}
+		b.WriteString("\n")
+		b.WriteString(a.styles.MenuItem.Render(fmt.Sprintf("%-13s %6d %4d %4d%% %6s %6s %6s", diff, sum.Played, sum.Won, sum.WinRate(), best, avg, med)))
+	}
+	if err != nil {
+		b.WriteString("\n\n" + a.styles.StatusError.Render("cannot read stats: "+err.Error()))
+	}
+	help := a.styles.Status.Render("Esc: back")
+	content := "\n" + title + "\n\n" + b.String() + "\n\n" + help + "\n"
 	panel := a.styles.Panel.Render(content)
 	if a.width > 0 && a.height > 0 {
 		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
@@ -221,8 +718,19 @@ func (a App) viewGame() string {
 	boardAndStatus := Render(a.game)
 
 	label := a.currentDiff
-	if a.currentDiff == "Daily" { label = "Daily Seed" }
+	if a.currentDiff == "Daily" {
+		label = "Daily " + dailyTier(a.currentSeed)
+		if a.currentSeed != generator.DailySeed(time.Now()) {
+			label = "Daily " + a.currentSeed + " · " + dailyTier(a.currentSeed) // 지난 데일리(아카이브)
+		}
+	}
 	headerText := label + " Mode"
+	if a.currentVar != game.Classic {
+		headerText += " · " + a.currentVar.String()
+	}
+	if seeded(a.currentDiff) && a.currentSeed != "" {
+		headerText += " · seed " + a.currentSeed
+	}
 	// Adaptive colors for headers
 	adaptiveColors := theme.NewAdaptiveColors(a.th)
 	gradientColors := adaptiveColors.GetGradientColors()
@@ -244,14 +752,21 @@ func (a App) viewGame() string {
 	case "Daily":
 		dailyGrad := gradientColors["daily"]
 		header = gradientText(headerText, dailyGrad[0], dailyGrad[1])
+	case "Minimal":
+		minimalGrad := gradientColors["minimal"]
+		header = gradientText(headerText, minimalGrad[0], minimalGrad[1])
+	case "Killer":
+		killerGrad := gradientColors["killer"]
+		header = gradientText(headerText, killerGrad[0], killerGrad[1])
 	default:
 		header = lipgloss.NewStyle().Foreground(lipgloss.Color(a.th.Palette.Accent)).Bold(true).Render(headerText)
 	}
 
 	headerCentered := lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, header)
 	centered := lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, boardAndStatus)
-	// 간격: 상단 1줄 + 헤더 + 1줄(빈 줄 보이도록 개행 2개) + 보드(내부 보드-상태 2줄) + 하단 1줄
-	body := "\n" + headerCentered + "\n\n" + centered + "\n"
+	code := lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, a.styles.Status.Render("Code: "+a.shareCode()))
+	// 간격: 상단 1줄 + 헤더 + 1줄(빈 줄 보이도록 개행 2개) + 보드(내부 보드-상태 2줄) + 공유 코드 + 하단 1줄
+	body := "\n" + headerCentered + "\n\n" + centered + "\n\n" + code + "\n"
 	panel := a.styles.Panel.Render(body)
 	if a.width > 0 && a.height > 0 {
 		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
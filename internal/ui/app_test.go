@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"punkdoku/internal/config"
+	"punkdoku/internal/game"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/save"
+	"punkdoku/internal/stats"
+)
+
+func TestAbandonSaved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sol, err := game.ParseGrid("123456789456789123789123456214365897365897214897214365531642978642978531978531642")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh := save.Game{Difficulty: "Hard", Seed: "s", Solution: sol}
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if (r+c)%2 == 0 {
+				fresh.Given[r][c], fresh.Values[r][c] = true, sol[r][c]
+			}
+		}
+	}
+	started := fresh
+	started.Values[0][1] = sol[0][1]
+	solved := fresh
+	solved.Values = sol
+	wrongFull := fresh
+	wrongFull.Values = sol
+	wrongFull.Values[0][1], wrongFull.Values[0][3] = sol[0][3], sol[0][1]
+
+	tests := []struct {
+		name string
+		sg   save.Game
+		loss bool
+	}{
+		{"untouched", fresh, false},
+		{"solved", solved, false},
+		{"started", started, true},
+		{"full but wrong", wrongFull, true},
+	}
+	for _, tt := range tests {
+		before, _ := stats.Load()
+		if err := save.Write(tt.sg); err != nil {
+			t.Fatal(err)
+		}
+		abandonSaved()
+		after, _ := stats.Load()
+		if got := len(after.Games) - len(before.Games); got != map[bool]int{false: 0, true: 1}[tt.loss] {
+			t.Errorf("%s: recorded %d games", tt.name, got)
+		}
+		if save.Exists() {
+			t.Errorf("%s: save not cleared", tt.name)
+		}
+	}
+}
+
+// Importing a file that is already filled in correctly is not a win.
+func TestImportSolvedNotRecorded(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	givens, err := game.ParseGrid("..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := game.NewBoardFromPuzzle(givens)
+	b.Values = *solveCopy(givens, game.Classic)
+	a := NewApp(config.Default())
+	defer a.Close()
+	a.game, _ = a.startCustom(puzzleio.FromBoard(b))
+	a.state = stateGame
+	if !a.game.Completed() {
+		t.Fatal("solved import not marked completed")
+	}
+	m, _ := a.Update(tea.KeyMsg{Type: tea.KeyRight})
+	a = m.(App)
+	m, _ = a.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	st, _ := stats.Load()
+	if len(st.Games) != 0 {
+		t.Errorf("recorded %+v", st.Games)
+	}
+}
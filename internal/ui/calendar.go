@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/stats"
+	"punkdoku/internal/theme"
+)
+
+// now is the clock of the calendar, swapped out in tests.
+var now = time.Now
+
+// updateCalendar moves the day cursor through the daily archive; Enter plays
+// the selected day's daily puzzle. Future days can't be selected.
+func (a App) updateCalendar(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tea.KeyMsg:
+		day := a.calDay
+		switch m.String() {
+		case "left", "h":
+			day = day.AddDate(0, 0, -1)
+		case "right", "l":
+			day = day.AddDate(0, 0, 1)
+		case "up", "k":
+			day = day.AddDate(0, 0, -7)
+		case "down", "j":
+			day = day.AddDate(0, 0, 7)
+		case "[", "pgup":
+			day = addMonths(day, -1)
+		case "]", "pgdown":
+			day = addMonths(day, 1)
+		case "enter":
+			return a, a.requestPuzzle(dailyRequest(a.calDay))
+		case "esc", "q", "m":
+			a.state = stateMenu
+			return a, nil
+		case "ctrl+c":
+			return a, tea.Quit
+		}
+		if today := now().UTC(); day.After(today) {
+			day = today
+		}
+		a.calDay = day
+	case tea.WindowSizeMsg:
+		a.width, a.height = m.Width, m.Height
+	}
+	return a, nil
+}
+
+// addMonths moves day by n months, keeping its day of the month or the last
+// day of shorter months: Mar 31 goes back to Feb 28, not on to Mar 3.
+func addMonths(day time.Time, n int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	d := day.Day()
+	if last := first.AddDate(0, 1, -1).Day(); d > last { d = last }
+	return first.AddDate(0, 0, d-1)
+}
+
+// solvedTimes lists every solved daily of month's month with its best time,
+// "--:--" when no solve was timed and "*" when it was solved late, three a line to match the grid's width.
+func solvedTimes(days map[string]stats.DailyEntry, month time.Time) string {
+	var parts []string
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		e := days[generator.DailySeed(d)]
+		if !e.Solved { continue }
+		clock := "--:--"
+		if e.Best > 0 { clock = formatClock(e.Best) }
+		late := " "
+		if !e.OnTime { late = "*" }
+		parts = append(parts, fmt.Sprintf("%2d %s%s", d.Day(), clock, late))
+	}
+	var b strings.Builder
+	for i, p := range parts {
+		switch {
+		case i == 0:
+		case i%3 == 0:
+			b.WriteString("\n")
+		default:
+			b.WriteString("  ")
+		}
+		b.WriteString(p)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// viewCalendar draws the month of the day cursor; "•" marks solved dailies.
+func (a App) viewCalendar() string {
+	adaptiveColors := theme.NewAdaptiveColors(a.th)
+	bannerGrad := adaptiveColors.GetGradientColors()["banner"]
+	accent := adaptiveColors.GetAccentColors()
+	st, _ := stats.Load()
+	days := st.Daily()
+	today := now().UTC()
+	sel := a.calDay
+
+	solvedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(accent["success"]))
+	selStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(accent["selected"])).Bold(true)
+
+	title := gradientText(sel.Format("January 2006"), bannerGrad[0], bannerGrad[1])
+	var b strings.Builder
+	b.WriteString(a.styles.Status.Render(" Mo  Tu  We  Th  Fr  Sa  Su"))
+	first := time.Date(sel.Year(), sel.Month(), 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(first.Weekday()) + 6) % 7 // Monday first
+	b.WriteString("\n" + strings.Repeat("    ", offset))
+	for d := first; d.Month() == sel.Month(); d = d.AddDate(0, 0, 1) {
+		key := generator.DailySeed(d)
+		mark := " "
+		if days[key].Solved { mark = "•" }
+		cell := fmt.Sprintf(" %2d%s", d.Day(), mark)
+		switch {
+		case key == generator.DailySeed(sel):
+			cell = selStyle.Render(fmt.Sprintf("[%2d%s", d.Day(), mark))
+		case d.After(today):
+			cell = a.styles.Status.Faint(true).Render(cell)
+		case days[key].Solved:
+			cell = solvedStyle.Render(cell)
+		default:
+			cell = a.styles.MenuItem.Render(cell)
+		}
+		b.WriteString(cell)
+		if (offset+d.Day())%7 == 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	e := days[generator.DailySeed(sel)]
+	day := generator.DailySeed(sel) + " · " + generator.DailyDifficulty(sel).String()
+	detail := day + " · not solved"
+	if e.Solved {
+		detail = day + " · solved"
+		if e.Best > 0 { detail += " in " + formatClock(e.Best) }
+		if !e.OnTime { detail += " (late)" }
+	}
+	if list := solvedTimes(days, sel); list != "" {
+		detail += "\n\n" + solvedStyle.Render(list)
+	}
+	streak := fmt.Sprintf("Streak: %d · Best: %d", a.streak, a.bestStreak)
+	help := a.styles.Status.Render("←→↑↓ day · [ ] month · Enter: play · Esc: back · *: solved late")
+	if a.genErr != "" {
+		help = a.styles.StatusError.Width(54).Render(a.genErr) + "\n\n" + help
+	}
+	content := "\n" + title + "\n\n" + strings.TrimRight(b.String(), "\n") + "\n\n" + a.styles.MenuItem.Render(detail) + "\n" + a.styles.Status.Render(streak) + "\n\n" + help + "\n"
+	panel := a.styles.Panel.Render(content)
+	if a.width > 0 && a.height > 0 {
+		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
+	}
+	return a.styles.App.Render(panel)
+}
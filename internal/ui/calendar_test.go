@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/stats"
+	"punkdoku/internal/theme"
+)
+
+func TestCalendarMonthKeys(t *testing.T) {
+	defer func(old func() time.Time) { now = old }(now)
+	now = func() time.Time { return time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) }
+
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		from time.Time
+		key  string
+		want time.Time
+	}{
+		{date(2026, 1, 31), "]", date(2026, 2, 28)},
+		{date(2026, 3, 31), "[", date(2026, 2, 28)},
+		{date(2024, 3, 31), "[", date(2024, 2, 29)},
+		{date(2026, 5, 31), "]", date(2026, 6, 30)},
+		{date(2026, 1, 15), "[", date(2025, 12, 15)},
+		{date(2025, 12, 31), "]", date(2026, 1, 31)},
+		// the future is out of reach: clamp to today
+		{date(2026, 9, 30), "]", now().UTC()},
+	}
+	for _, tt := range tests {
+		a := App{state: stateCalendar, calDay: tt.from}
+		m, _ := a.updateCalendar(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+		if got := m.(App).calDay; !got.Equal(tt.want) {
+			t.Errorf("%s on %s: got %s, want %s", tt.key, tt.from.Format("2006-01-02"), got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestSolvedTimes(t *testing.T) {
+	days := map[string]stats.DailyEntry{
+		"2026-10-01": {Solved: true, OnTime: true, Best: 4*time.Minute + 12*time.Second},
+		"2026-10-03": {Solved: true, OnTime: false, Best: 75 * time.Second},
+		"2026-10-09": {Solved: true, OnTime: true},
+		"2026-10-17": {Solved: true, OnTime: true, Best: 10 * time.Minute},
+		"2026-10-18": {},
+		"2026-09-30": {Solved: true, OnTime: true, Best: time.Minute},
+	}
+	got := solvedTimes(days, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC))
+	want := " 1 04:12    3 01:15*   9 --:-- \n17 10:00"
+	if got != want {
+		t.Errorf("solvedTimes:\n got %q\nwant %q", got, want)
+	}
+	if got := solvedTimes(days, time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)); got != "" {
+		t.Errorf("month without solves: %q", got)
+	}
+}
+
+// Every solved day of the month shows its time, not only the selected one.
+func TestCalendarShowsSolvedTimes(t *testing.T) {
+	defer func(old func() time.Time) { now = old }(now)
+	now = func() time.Time { return time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) }
+	t.Setenv("HOME", t.TempDir())
+	for _, d := range []int{2, 11} {
+		date := time.Date(2026, 10, d, 8, 0, 0, 0, time.UTC)
+		r := stats.Record{Difficulty: "Daily", Seed: generator.DailySeed(date), Date: date, Won: true, Timed: true, Elapsed: time.Duration(d) * time.Minute}
+		if _, err := stats.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	th := theme.Punk()
+	a := App{state: stateCalendar, calDay: now().UTC(), th: th, styles: BuildStyles(th)}
+	view := a.viewCalendar()
+	for _, want := range []string{" 2 02:00", "11 11:00"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("calendar view lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"punkdoku/internal/game"
+	"punkdoku/internal/puzzleio"
+	"punkdoku/internal/solver"
+)
+
+// ImportPuzzle loads a custom puzzle from either an 81-cell string or the
+// path of a puzzle file (any puzzleio format; the first puzzle is used), and
+// validates its givens (consistent, exactly one solution) before it can be
+// played. Values and pencil marks stored in the file are kept.
+func ImportPuzzle(input string) (puzzleio.Puzzle, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return puzzleio.Puzzle{}, errors.New("enter a puzzle or a file path")
+	}
+	var p puzzleio.Puzzle
+	if _, statErr := os.Stat(input); statErr == nil {
+		ps, err := puzzleio.ReadFile(input)
+		if err != nil {
+			return puzzleio.Puzzle{}, err
+		}
+		if len(ps) == 0 {
+			return puzzleio.Puzzle{}, fmt.Errorf("%s: no puzzle found", input)
+		}
+		p = ps[0]
+	} else if looksLikePath(input) {
+		return puzzleio.Puzzle{}, fmt.Errorf("cannot read %s: %w", input, statErr)
+	} else {
+		g, err := game.ParseGrid(input)
+		if err != nil {
+			return puzzleio.Puzzle{}, fmt.Errorf("not a puzzle: %w", err)
+		}
+		p = puzzleio.Puzzle{Board: game.NewBoardFromPuzzle(g)}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := solver.Validate(ctx, solver.Grid(p.Grid())); err != nil {
+		return puzzleio.Puzzle{}, err
+	}
+	return p, nil
+}
+
+// looksLikePath tells a missing file from a mistyped puzzle: it has a path
+// separator or the extension of a puzzle file, in any case.
+func looksLikePath(s string) bool {
+	if strings.ContainsAny(s, "/\\") { return true }
+	return strings.EqualFold(filepath.Ext(s), ".txt") || puzzleio.FormatForPath(s) != puzzleio.Line
+}
@@ -0,0 +1,26 @@
+package ui
+
+import "testing"
+
+func TestLooksLikePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"puzzles/book.txt", true},
+		{`C:\sudoku\one`, true},
+		{"NEWSPAPER.SDK", true},
+		{"saved.Sdx", true},
+		{"simple.ss", true},
+		{"export.opensudoku", true},
+		{"list.TXT", true},
+		{"..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.", false},
+		{"5.ss..2.sdk..9", false},
+		{"hello", false},
+	}
+	for _, tt := range tests {
+		if got := looksLikePath(tt.in); got != tt.want {
+			t.Errorf("looksLikePath(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -10,6 +10,8 @@ type KeyMap struct {
 	Undo, Redo            key.Binding
 	ToggleAuto            key.Binding
 	ToggleTimer           key.Binding
+	NoteMode              key.Binding
+	Hint                  key.Binding
 	Help                  key.Binding
 	MainMenu              key.Binding
 }
@@ -24,6 +26,8 @@ func DefaultKeyMap() KeyMap {
 		Redo:        key.NewBinding(key.WithKeys("ctrl+y", "ctrl+r"), key.WithHelp("Ctrl+Y/R", "Redo/다시하기")),
 		ToggleAuto:  key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "Auto-Check/자동 체크")),
 		ToggleTimer: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "Timer/타이머")),
+		NoteMode:    key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Notes/메모")),
+		Hint:        key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Hint/힌트")),
 		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "Help/도움말")),
 		MainMenu:    key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "Main/메인")),
 	}
@@ -43,6 +47,8 @@ func (km *KeyMap) ApplyBindings(bindings map[string][]string) {
 	if v, ok := bindings["redo"]; ok { set(&km.Redo, v, "Redo/다시하기") }
 	if v, ok := bindings["auto"]; ok { set(&km.ToggleAuto, v, "Auto-Check/자동 체크") }
 	if v, ok := bindings["timer"]; ok { set(&km.ToggleTimer, v, "Timer/타이머") }
+	if v, ok := bindings["note"]; ok { set(&km.NoteMode, v, "Notes/메모") }
+	if v, ok := bindings["hint"]; ok { set(&km.Hint, v, "Hint/힌트") }
 	if v, ok := bindings["help"]; ok { set(&km.Help, v, "Help/도움말") }
 	if v, ok := bindings["main"]; ok { set(&km.MainMenu, v, "Main/메인") }
 }
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"punkdoku/internal/game"
+	"punkdoku/internal/generator"
+	"punkdoku/internal/theme"
+)
+
+// maxRetries is how often a failed random puzzle is retried with a fresh
+// seed. A chosen seed (typed, shared or daily) always fails the same way, so
+// it is never retried.
+const maxRetries = 3
+
+// genRequest describes a puzzle to generate in the background.
+type genRequest struct {
+	id    int
+	label string // menu label: a difficulty, "Daily" or "Killer"
+	seed  string
+	sym   generator.Symmetry
+	// variant adds X-Sudoku or Hyper regions to a difficulty puzzle
+	variant game.Variant
+	// fresh is set when seed came from newSeed, so a retry may pick another
+	fresh bool
+	tries int
+	// from is the screen to go back to on cancel or failure
+	from appState
+}
+
+func dailyRequest(date time.Time) genRequest {
+	return genRequest{label: "Daily", seed: generator.DailySeed(date)}
+}
+
+// generated is a finished puzzle; killer is set for Killer Sudoku, whose
+// cages and solution don't fit in a grid.
+type generated struct {
+	grid   generator.Grid
+	killer *generator.Killer
+}
+
+func (r genRequest) generate(ctx context.Context) (generated, error) {
+	var p generator.Params
+	seed := r.seed
+	switch r.label {
+	case "Killer":
+		k, err := generator.GenerateKiller(ctx, seed)
+		if err != nil { return generated{}, err }
+		return generated{grid: k.Givens, killer: &k}, nil
+	case "Daily":
+		date, err := time.Parse("2006-01-02", r.seed)
+		if err != nil { return generated{}, err }
+		p = generator.ParamsFor(generator.DailyDifficulty(date))
+		seed = generator.DailySeed(date)
+	default:
+		d, err := generator.ParseDifficulty(r.label)
+		if err != nil { return generated{}, err }
+		p = generator.ParamsFor(d)
+		p.Symmetry = r.sym
+		p.Variant = r.variant
+	}
+	g, err := generator.GenerateContext(ctx, p, seed)
+	return generated{grid: g}, err
+}
+
+// puzzleMsg reports a finished background generation.
+type puzzleMsg struct {
+	req    genRequest
+	puzzle generated
+	err    error
+}
+
+func generateCmd(ctx context.Context, r genRequest) tea.Cmd {
+	return func() tea.Msg {
+		p, err := r.generate(ctx)
+		return puzzleMsg{req: r, puzzle: p, err: err}
+	}
+}
+
+// requestPuzzle shows the loading screen and generates r off the UI loop.
+// Only the latest request is kept; Esc cancels it through its context.
+func (a *App) requestPuzzle(r genRequest) tea.Cmd {
+	a.genID++
+	r.id = a.genID
+	r.from = a.state
+	a.pending = r
+	a.genErr = ""
+	a.state = stateLoading
+	var ctx context.Context
+	ctx, a.cancelGen = context.WithCancel(context.Background())
+	return tea.Batch(a.spinner.Tick, generateCmd(ctx, r))
+}
+
+func (a App) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tea.KeyMsg:
+		switch m.String() {
+		case "ctrl+c":
+			return a, tea.Quit
+		case "esc", "q":
+			a.cancelGen()
+			a.genID++ // drop the cancelled result
+			return a.leaveLoading("")
+		}
+	case puzzleMsg:
+		if m.req.id != a.genID { return a, nil }
+		if m.err != nil {
+			if m.req.fresh && m.req.tries < maxRetries {
+				r := m.req
+				r.tries++
+				r.seed = newSeed()
+				a.pending = r
+				a.cancelGen()
+				var ctx context.Context
+				ctx, a.cancelGen = context.WithCancel(context.Background())
+				return a, generateCmd(ctx, r)
+			}
+			a.cancelGen()
+			return a.leaveLoading(fmt.Sprintf("Could not generate a %s puzzle: %v", m.req.label, m.err))
+		}
+		a.cancelGen()
+		gm, cmd := a.begin(m.req, m.puzzle)
+		a.game = gm
+		a.state = stateGame
+		return a, cmd
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		a.spinner, cmd = a.spinner.Update(m)
+		return a, cmd
+	case tea.WindowSizeMsg:
+		a.width, a.height = m.Width, m.Height
+	}
+	return a, nil
+}
+
+// leaveLoading returns to the screen the request came from, showing errMsg there.
+func (a App) leaveLoading(errMsg string) (tea.Model, tea.Cmd) {
+	a.state = a.pending.from
+	if a.state == stateImport {
+		a.importErr = errMsg
+		return a, a.importInput.Focus()
+	}
+	a.genErr = errMsg
+	return a, nil
+}
+
+func (a App) viewLoading() string {
+	adaptiveColors := theme.NewAdaptiveColors(a.th)
+	bannerGrad := adaptiveColors.GetGradientColors()["banner"]
+	what := a.pending.label
+	if a.pending.variant != game.Classic {
+		what += " " + a.pending.variant.String()
+	}
+	if what == "Daily" {
+		what = "Daily " + a.pending.seed
+	}
+	title := gradientText("Generating "+what+" puzzle", bannerGrad[0], bannerGrad[1])
+	step := "carving clues…"
+	if a.pending.label == "Killer" { step = "building cages…" }
+	status := a.spinner.View() + " " + a.styles.MenuItem.Render(step)
+	if a.pending.tries > 0 {
+		status += a.styles.Status.Render(fmt.Sprintf("  (retry %d/%d)", a.pending.tries, maxRetries))
+	}
+	help := a.styles.Status.Render("Esc: cancel")
+	content := "\n" + title + "\n\n" + status + "\n\n" + help + "\n"
+	panel := a.styles.Panel.Render(content)
+	if a.width > 0 && a.height > 0 {
+		return a.styles.App.Render(lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, panel))
+	}
+	return a.styles.App.Render(panel)
+}
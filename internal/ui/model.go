@@ -1,15 +1,19 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
 	"punkdoku/internal/config"
 	"punkdoku/internal/game"
 	"punkdoku/internal/generator"
+	"punkdoku/internal/save"
 	"punkdoku/internal/solver"
+	"punkdoku/internal/stats"
 	"punkdoku/internal/theme"
 )
 
@@ -24,6 +28,9 @@ type Model struct {
 
 	board        game.Board
 	solution     game.Grid
+	// variant's extra regions are in board.Regions; inRegion shades them
+	variant      game.Variant
+	inRegion     [9][9]bool
 	cursorRow    int
 	cursorCol    int
 	autoCheck    bool
@@ -31,20 +38,114 @@ type Model struct {
 	startTime    time.Time
 	elapsed      time.Duration
 	completed    bool
+	noteMode     bool
 
 	undoStack    []game.Move
 	redoStack    []game.Move
 	flashes      map[[2]int]time.Time
 	showHelp     bool
+
+	// hint state: message in the status line, highlighted cells, and how far
+	// into the deduction chain for the current board the player has asked
+	hintText     string
+	hintCells    [9][9]bool
+	hintIdx      int
+	hintBoard    game.Grid
+	hintsUsed    int
+
+	// mistakes counts digits entered that differ from the solution; newBest
+	// and recorded are set by the App once the finished game is in the stats
+	mistakes     int
+	newBest      bool
+	recorded     bool
 }
 
 func New(p generator.Grid, th theme.Theme, cfg config.Config) Model {
+	return NewVariant(p, game.Classic, th, cfg)
+}
+
+// NewVariant starts a puzzle played with the extra regions of v.
+func NewVariant(p generator.Grid, v game.Variant, th theme.Theme, cfg config.Config) Model {
 	b := game.NewBoardFromPuzzle(game.Grid(p))
+	b.Regions = v.Regions()
 	// Solve once for auto-check
 	sg := b.Values
-	if s := solveCopy(b.Values); s != nil {
+	if s := solveCopy(b.Values, v); s != nil {
 		sg = *s
 	}
+	m := newModel(b, sg, th, cfg)
+	m.variant = v
+	return m
+}
+
+// NewKiller starts a Killer Sudoku; the solution comes from the generator
+// since the givens alone don't determine it.
+func NewKiller(k generator.Killer, th theme.Theme, cfg config.Config) Model {
+	b := game.NewBoardFromPuzzle(game.Grid(k.Givens))
+	b.Cages = k.Cages
+	return newModel(b, game.Grid(k.Solution), th, cfg)
+}
+
+// Resume rebuilds a Model from a saved game; the timer continues from the saved elapsed time.
+func Resume(sg save.Game, th theme.Theme, cfg config.Config) Model {
+	sol := sg.Solution
+	// Killer givens don't pin the solution; those saves always carry it
+	v, _ := game.ParseVariant(sg.Variant)
+	if !allFilled(sol) && len(sg.Cages) == 0 {
+		if s := solveCopy(sg.Puzzle, v); s != nil {
+			sol = *s
+		}
+	}
+	m := newModel(sg.Board(), sol, th, cfg)
+	m.variant = v
+	m.undoStack = sg.Undo
+	m.redoStack = sg.Redo
+	m.elapsed = sg.Elapsed
+	m.startTime = time.Now().Add(-sg.Elapsed)
+	m.hintsUsed = sg.HintsUsed
+	m.mistakes = sg.Mistakes
+	m.completed = isSolved(m.board.Values, m.solution)
+	return m
+}
+
+// Givens is the puzzle as dealt: the given digits only.
+func (m Model) Givens() game.Grid {
+	var puzzle game.Grid
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if m.board.Given[r][c] { puzzle[r][c] = m.board.Values[r][c] }
+		}
+	}
+	return puzzle
+}
+
+// Snapshot captures the current game for the save file.
+func (m Model) Snapshot(difficulty, seed string) save.Game {
+	elapsed := m.elapsed
+	if m.timerEnabled && !m.completed {
+		elapsed = time.Since(m.startTime)
+	}
+	return save.Game{
+		Difficulty: difficulty,
+		Seed:       seed,
+		Puzzle:     m.Givens(),
+		Solution:   m.solution,
+		Given:      m.board.Given,
+		Values:     m.board.Values,
+		Notes:      m.board.Notes,
+		Cages:      m.board.Cages,
+		Undo:       m.undoStack,
+		Redo:       m.redoStack,
+		Elapsed:    elapsed,
+		HintsUsed:  m.hintsUsed,
+		Mistakes:   m.mistakes,
+	}
+}
+
+// Completed reports whether the puzzle has been solved.
+func (m Model) Completed() bool { return m.completed }
+
+func newModel(b game.Board, sg game.Grid, th theme.Theme, cfg config.Config) Model {
 	km := DefaultKeyMap()
 	km.ApplyBindings(cfg.Bindings)
 	m := Model{
@@ -53,6 +154,7 @@ func New(p generator.Grid, th theme.Theme, cfg config.Config) Model {
 		theme:        th,
 		board:        b,
 		solution:     sg,
+		inRegion:     game.RegionMap(b.Regions),
 		cursorRow:    0,
 		cursorCol:    0,
 		autoCheck:    cfg.AutoCheck,
@@ -63,10 +165,12 @@ func New(p generator.Grid, th theme.Theme, cfg config.Config) Model {
 	return m
 }
 
-func solveCopy(g game.Grid) *game.Grid {
+func solveCopy(g game.Grid, v game.Variant) *game.Grid {
 	var sg solver.Grid
 	for r := 0; r < 9; r++ { for c := 0; c < 9; c++ { sg[r][c] = g[r][c] } }
-	if solver.Solve(&sg, 2*time.Second) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if solver.SolveWith(ctx, &sg, generator.SolverRegions(v)) {
 		var out game.Grid
 		for r := 0; r < 9; r++ { for c := 0; c < 9; c++ { out[r][c] = sg[r][c] } }
 		return &out
@@ -119,6 +223,14 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	}
+	if key.Matches(k, m.keymap.NoteMode) {
+		m.noteMode = !m.noteMode
+		return m, nil
+	}
+	if key.Matches(k, m.keymap.Hint) {
+		m = m.applyHint()
+		return m, nil
+	}
 	if key.Matches(k, m.keymap.Undo) {
 		m = m.applyUndo()
 		return m, nil
@@ -138,9 +250,15 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "right", "l":
 		m.cursorCol = clamp(m.cursorCol+1, 0, 8)
 	case " ", "0":
+		if m.noteMode {
+			return m.applyNote(0)
+		}
 		return m.applyInput(0)
 	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		v := uint8(s[0]-'0')
+		if m.noteMode {
+			return m.applyNote(v)
+		}
 		return m.applyInput(v)
 	case "q", "esc", "ctrl+c":
 		return m, tea.Quit
@@ -154,21 +272,50 @@ func (m Model) applyInput(v uint8) (tea.Model, tea.Cmd) {
 	}
 	prev, ok := m.board.SetValue(m.cursorRow, m.cursorCol, v)
 	if !ok { return m, nil }
-	mv := game.Move{Row: m.cursorRow, Col: m.cursorCol, Prev: prev, Next: v, At: time.Now()}
+	m = m.clearHint()
+	notes := m.board.Notes[m.cursorRow][m.cursorCol]
+	mv := game.Move{Row: m.cursorRow, Col: m.cursorCol, Prev: prev, Next: v, PrevNotes: notes, NextNotes: notes, At: time.Now()}
+	// 숫자를 놓으면 같은 행/열/블록의 메모에서 해당 숫자를 지움
+	mv.Peers = m.board.EliminateNote(m.cursorRow, m.cursorCol, v)
 	m.undoStack = append(m.undoStack, mv)
 	m.redoStack = nil
 	m.flashes[[2]int{m.cursorRow, m.cursorCol}] = time.Now().Add(120 * time.Millisecond)
+	if v != 0 && v != m.solution[m.cursorRow][m.cursorCol] {
+		m.mistakes++
+	}
 	if isSolved(m.board.Values, m.solution) {
 		m.completed = true
+		if m.timerEnabled {
+			m.elapsed = time.Since(m.startTime)
+		}
 	}
 	return m, tea.Tick(130*time.Millisecond, func(time.Time) tea.Msg { return flashDoneMsg{Row: mv.Row, Col: mv.Col} })
 }
 
+// applyNote toggles pencil mark v on the cursor cell; v == 0 clears all marks.
+func (m Model) applyNote(v uint8) (tea.Model, tea.Cmd) {
+	var prev, next uint16
+	var ok bool
+	if v == 0 {
+		prev, ok = m.board.ClearNotes(m.cursorRow, m.cursorCol)
+	} else {
+		prev, next, ok = m.board.ToggleNote(m.cursorRow, m.cursorCol, v)
+	}
+	if !ok { return m, nil }
+	m = m.clearHint()
+	cur := m.board.Values[m.cursorRow][m.cursorCol]
+	mv := game.Move{Row: m.cursorRow, Col: m.cursorCol, Prev: cur, Next: cur, PrevNotes: prev, NextNotes: next, At: time.Now()}
+	m.undoStack = append(m.undoStack, mv)
+	m.redoStack = nil
+	return m, nil
+}
+
 func (m Model) applyUndo() Model {
 	if len(m.undoStack) == 0 { return m }
 	last := m.undoStack[len(m.undoStack)-1]
 	m.undoStack = m.undoStack[:len(m.undoStack)-1]
-	m.board.Values[last.Row][last.Col] = last.Prev
+	m = m.clearHint()
+	m.board.Undo(last)
 	m.redoStack = append(m.redoStack, last)
 	m.cursorRow, m.cursorCol = last.Row, last.Col
 	m.completed = isSolved(m.board.Values, m.solution)
@@ -179,13 +326,83 @@ func (m Model) applyRedo() Model {
 	if len(m.redoStack) == 0 { return m }
 	last := m.redoStack[len(m.redoStack)-1]
 	m.redoStack = m.redoStack[:len(m.redoStack)-1]
-	m.board.Values[last.Row][last.Col] = last.Next
+	m = m.clearHint()
+	m.board.Redo(last)
 	m.undoStack = append(m.undoStack, last)
 	m.cursorRow, m.cursorCol = last.Row, last.Col
 	m.completed = isSolved(m.board.Values, m.solution)
 	return m
 }
 
+// applyHint explains the next logical deduction for the current board.
+// Wrong digits are pointed out first, since no logic can follow from them;
+// on a Killer board that is all a hint does.
+// Pressing the key again on an unchanged board walks further along the
+// chain of eliminations up to the next placement.
+func (m Model) applyHint() Model {
+	if m.completed { return m }
+	var wrong []solver.Cell
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			v := m.board.Values[r][c]
+			if v != 0 && !m.board.Given[r][c] && v != m.solution[r][c] {
+				wrong = append(wrong, solver.Cell{Row: r, Col: c})
+			}
+		}
+	}
+	m.hintCells = [9][9]bool{}
+	if len(wrong) > 0 {
+		for _, c := range wrong { m.hintCells[c.Row][c.Col] = true }
+		m.hintText = fmt.Sprintf("Mistake: %s does not fit the solution, fix it first", wrong[0])
+		m.hintsUsed++
+		return m
+	}
+	if m.board.Cages != nil {
+		// the logical solver knows no cage sums, and Killer givens alone say little
+		m.hintText = "No hints in Killer: only mistakes are pointed out"
+		return m
+	}
+	chain := hintChain(solver.Grid(m.board.Values), m.variant)
+	if len(chain) == 0 {
+		m.hintText = "No logical step found: trial and error needed"
+		return m
+	}
+	if m.hintText != "" && m.hintBoard == m.board.Values {
+		if m.hintIdx >= len(chain)-1 {
+			return m // already showing the placement; don't count it twice
+		}
+		m.hintIdx++
+	} else {
+		m.hintIdx = 0
+	}
+	st := chain[m.hintIdx]
+	for _, c := range st.Cells { m.hintCells[c.Row][c.Col] = true }
+	for _, p := range st.Placements { m.hintCells[p.Row][p.Col] = true }
+	m.hintText = st.Description()
+	m.hintBoard = m.board.Values
+	m.hintsUsed++
+	return m
+}
+
+// hintChain returns the logical steps from g up to and including the next
+// placement, using the extra regions of v.
+func hintChain(g solver.Grid, v game.Variant) []solver.Step {
+	res := solver.SolveLogicalWith(g, generator.SolverRegions(v))
+	for i, st := range res.Steps {
+		if len(st.Placements) > 0 {
+			return res.Steps[:i+1]
+		}
+	}
+	return res.Steps
+}
+
+func (m Model) clearHint() Model {
+	m.hintText = ""
+	m.hintCells = [9][9]bool{}
+	m.hintIdx = 0
+	return m
+}
+
 func clamp(v, lo, hi int) int {
 	if v < lo { return lo }
 	if v > hi { return hi }
@@ -208,12 +425,23 @@ func (m Model) StatusLine() string {
 		} else {
 			completeText = "✭ Clear! Tap 'm' to quit ✭"
 		}
-		return gradientText(completeText, completeGrad[0], completeGrad[1])
+		lines := []string{gradientText(completeText, completeGrad[0], completeGrad[1])}
+		if m.newBest {
+			lines = append(lines, m.styles.BoolTrue.Render("✭ New best! ✭"))
+		}
+		if m.hintsUsed > 0 {
+			lines = append(lines, m.styles.Status.Render(hintCountText(m.hintsUsed)))
+		}
+		return lipgloss.JoinVertical(lipgloss.Center, lines...)
 	}
 	// All filled but not solved → Try again
 	if allFilled(m.board.Values) && !isSolved(m.board.Values, m.solution) {
 		return m.styles.StatusError.Render("✭ Try again... ✭")
 	}
+	// Hint explanation replaces the status segments until the next move
+	if m.hintText != "" {
+		return m.styles.Hint.Width(46).Align(lipgloss.Center).Render(m.hintText)
+	}
 	// Normal status (fixed width segments)
 	var auto string
 	if m.autoCheck {
@@ -235,11 +463,43 @@ func (m Model) StatusLine() string {
 	
 	separator := m.styles.Status.Render(" | ")
 	undoHint := m.styles.Status.Render("Undo: u")
+	if m.noteMode {
+		undoHint = m.styles.Status.Render("Note: ") + m.styles.BoolTrue.Render("ON ")
+	}
 	mainHint := m.styles.Status.Render("Main: m")
 	
 	return auto + separator + timerStr + separator + undoHint + separator + mainHint
 }
 
+// record turns the current game into a stats entry.
+func (m Model) record(difficulty, seed string, won bool) stats.Record {
+	elapsed := m.elapsed
+	if m.timerEnabled && !m.completed {
+		elapsed = time.Since(m.startTime)
+	}
+	return stats.Record{
+		Difficulty: difficulty,
+		Seed:       seed,
+		Elapsed:    elapsed,
+		Timed:      m.timerEnabled,
+		Hints:      m.hintsUsed,
+		Mistakes:   m.mistakes,
+		Won:        won,
+		Date:       time.Now(),
+	}
+}
+
+// formatClock renders a duration as MM:SS like the in-game timer.
+func formatClock(d time.Duration) string {
+	secs := int(d.Truncate(time.Second).Seconds())
+	return fmt.Sprintf("%02d:%02d", (secs/60)%100, secs%60)
+}
+
+func hintCountText(n int) string {
+	if n == 1 { return "1 hint used" }
+	return fmt.Sprintf("%d hints used", n)
+}
+
 func allFilled(g game.Grid) bool {
 	for r := 0; r < 9; r++ {
 		for c := 0; c < 9; c++ {
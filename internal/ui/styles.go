@@ -24,6 +24,11 @@ type UIStyles struct {
 	CellSelected  lipgloss.Style
 	CellDuplicate lipgloss.Style
 	CellConflict  lipgloss.Style
+	CellNote      lipgloss.Style
+	CellHint      lipgloss.Style
+	CellRegion    lipgloss.Style
+	CageLine      lipgloss.Style
+	CageSum       lipgloss.Style
 	Status        lipgloss.Style
 	StatusError   lipgloss.Style
 
@@ -70,6 +75,11 @@ func BuildStyles(t theme.Theme) UIStyles {
 		CellSelected:  lipgloss.NewStyle().Background(lipgloss.Color(t.Palette.CellSelectedBG)).Foreground(lipgloss.Color(t.Palette.CellSelectedFG)).Padding(0, 1).Bold(true),
 		CellDuplicate: lipgloss.NewStyle().Background(lipgloss.Color(t.Palette.CellDuplicateBG)).Padding(0, 1),
 		CellConflict:  lipgloss.NewStyle().Background(lipgloss.Color(t.Palette.CellConflictBG)).Padding(0, 1).Bold(true),
+		CellNote:      lipgloss.NewStyle().Foreground(gray),
+		CellHint:      lipgloss.NewStyle().Background(lipgloss.Color(t.Palette.CellHintBG)).Padding(0, 1).Bold(true),
+		CellRegion:    lipgloss.NewStyle().Background(lipgloss.Color(t.Palette.CellRegionBG)).Foreground(lipgloss.Color(t.Palette.CellBaseFG)).Padding(0, 1),
+		CageLine:      lipgloss.NewStyle().Foreground(gray).Faint(true),
+		CageSum:       lipgloss.NewStyle().Foreground(accent),
 		Status:        lipgloss.NewStyle().Foreground(statusColor), // 다크모드에서 회색, 화이트모드에서 검은색
 		StatusError:   lipgloss.NewStyle().Foreground(lipgloss.Color(accentColors["error"])).Bold(true),
 
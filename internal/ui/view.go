@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,14 +13,17 @@ func boardString(m Model) string {
 	var b strings.Builder
 	var dup [9][9]bool
 	if m.autoCheck {
-		dup = game.DuplicateMap(m.board.Values, m.cursorRow, m.cursorCol)
+		dup = game.DuplicateMap(m.board.Values, m.cursorRow, m.cursorCol, m.board.Constraints()...)
 	}
 	var conf [9][9]bool
 	if m.autoCheck {
-		conf = game.ConflictMap(m.board.Values, m.board.Given)
+		conf = game.ConflictMap(m.board.Values, m.board.Given, m.board.Constraints()...)
 	}
 	// 셀의 시각적 폭 계산(패딩 포함)
 	cellWidth := lipgloss.Width(m.styles.Cell.Render("0"))
+	if len(m.board.Cages) > 0 {
+		return m.cageBoardString(dup, conf, cellWidth)
+	}
 
 	buildLine := func(left, mid, right string) string {
 		seg := strings.Repeat("─", cellWidth)
@@ -69,6 +73,88 @@ func boardString(m Model) string {
 	return b.String()
 }
 
+// cageBoardString is the Killer layout: every cell is framed so cage edges can
+// be drawn as dashed lines between cells, with each cage's sum written into
+// the edge above its top-left cell. Block lines stay solid and win over cage
+// edges.
+func (m Model) cageBoardString(dup, conf [9][9]bool, cellWidth int) string {
+	cage := game.CageIndex(m.board.Cages)
+	labels := map[[2]int]string{}
+	for _, k := range m.board.Cages {
+		labels[k.Anchor()] = strconv.Itoa(k.Sum)
+	}
+	// 바깥쪽은 -1(케이지 없음)으로 취급
+	at := func(r, c int) int {
+		if !game.InBounds(r, c) { return -1 }
+		return cage[r][c]
+	}
+	edgeH := func(r, c int) bool { return at(r-1, c) != at(r, c) } // (r,c) 위쪽 경계
+	edgeV := func(r, c int) bool { return at(r, c-1) != at(r, c) } // (r,c) 왼쪽 경계
+
+	junction := func(r, c int) string {
+		hBlock, vBlock := r%3 == 0, c%3 == 0
+		switch {
+		case hBlock && vBlock:
+			corners := [3][3]string{{"╭", "┬", "╮"}, {"├", "┼", "┤"}, {"╰", "┴", "╯"}}
+			return m.styles.RowSep.Render(corners[min(r, 1)+r/9][min(c, 1)+c/9])
+		case hBlock:
+			return m.styles.RowSep.Render("─")
+		case vBlock:
+			return m.styles.ColSep.Render("│")
+		}
+		horiz := edgeH(r, c-1) || edgeH(r, c)
+		vert := edgeV(r-1, c) || edgeV(r, c)
+		switch {
+		case horiz && vert:
+			return m.styles.CageLine.Render("·")
+		case horiz:
+			return m.styles.CageLine.Render("╌")
+		case vert:
+			return m.styles.CageLine.Render("┆")
+		}
+		return " "
+	}
+
+	var b strings.Builder
+	for r := 0; r <= 9; r++ {
+		// (r-1)행과 r행 사이의 가로줄
+		for c := 0; c < 9; c++ {
+			b.WriteString(junction(r, c))
+			seg := " "
+			style := lipgloss.NewStyle()
+			if r%3 == 0 {
+				seg, style = "─", m.styles.RowSep
+			} else if edgeH(r, c) {
+				seg, style = "╌", m.styles.CageLine
+			}
+			if label, ok := labels[[2]int{r, c}]; ok {
+				b.WriteString(m.styles.CageSum.Render(label))
+				b.WriteString(style.Render(strings.Repeat(seg, cellWidth-len(label))))
+			} else {
+				b.WriteString(style.Render(strings.Repeat(seg, cellWidth)))
+			}
+		}
+		b.WriteString(junction(r, 9))
+		if r == 9 { break }
+		b.WriteString("\n")
+		for c := 0; c <= 9; c++ {
+			switch {
+			case c%3 == 0:
+				b.WriteString(m.styles.ColSep.Render("│"))
+			case edgeV(r, c):
+				b.WriteString(m.styles.CageLine.Render("┆"))
+			default:
+				b.WriteString(" ")
+			}
+			if c < 9 {
+				b.WriteString(m.cellView(r, c, dup[r][c], conf[r][c]))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func Render(m Model) string {
 	board := boardString(m)
 	// 고정 폭으로 상태줄 중앙 정렬 (46은 스도쿠 보드의 실제 폭)
@@ -82,8 +168,14 @@ func (m Model) cellView(r, c int, isDup, isConf bool) string {
 	str := "·"
 	if v != 0 { str = string('0'+v) }
 	style := m.styles.Cell
+	if m.inRegion[r][c] {
+		style = m.styles.CellRegion
+	}
 	if m.board.Given[r][c] {
 		style = m.styles.CellFixed
+		if m.inRegion[r][c] {
+			style = style.Background(m.styles.CellRegion.GetBackground())
+		}
 	}
 	if isDup {
 		style = m.styles.CellDuplicate
@@ -91,6 +183,9 @@ func (m Model) cellView(r, c int, isDup, isConf bool) string {
 	if isConf {
 		style = m.styles.CellConflict
 	}
+	if m.hintCells[r][c] {
+		style = m.styles.CellHint
+	}
 	if r == m.cursorRow && c == m.cursorCol {
 		style = m.styles.CellSelected
 	}
@@ -99,5 +194,29 @@ func (m Model) cellView(r, c int, isDup, isConf bool) string {
 			style = style.Bold(true)
 		}
 	}
+	if notes := m.board.Notes[r][c]; v == 0 && notes != 0 {
+		// 메모는 패딩 없이 셀 폭(3칸) 전체를 사용
+		style = style.UnsetPadding()
+		if r != m.cursorRow || c != m.cursorCol {
+			style = style.Foreground(m.styles.CellNote.GetForeground())
+		}
+		return style.Render(notesString(notes))
+	}
 	return style.Render(str)
 }
+
+// notesString draws the pencil marks as a 3x3 sub-grid squeezed into three
+// braille glyphs: glyph i is column i, its left dots are rows 1-3 (1 2 3 / 4 5 6 / 7 8 9).
+func notesString(notes uint16) string {
+	var out [3]rune
+	for col := 0; col < 3; col++ {
+		ch := rune(0x2800)
+		for row := 0; row < 3; row++ {
+			if notes&game.NoteBit(uint8(row*3+col+1)) != 0 {
+				ch |= 1 << row
+			}
+		}
+		out[col] = ch
+	}
+	return string(out[:])
+}